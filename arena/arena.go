@@ -0,0 +1,49 @@
+package arena
+
+/* A block allocator hands out nodes of linked structures from larger
+ * blocks, so pushing n elements costs about n/size allocations instead
+ * of n, and the nodes of a block lie next to each other in memory.
+ *
+ * The arena only keeps the block it currently allocates from. Full blocks
+ * are referenced by their nodes alone, so the garbage collector frees a
+ * block once none of its nodes is reachable any more. A single live node
+ * keeps its whole block alive, which is the price for fewer allocations.
+ * Nodes are never handed back one by one; Free drops the arena wholesale.
+ */
+
+// number of nodes per block used by New with size 0
+const DefaultBlockSize = 256
+
+// generic block allocator structure
+type Arena[N any] struct {
+	block []N // unused rest of the current block
+	size  int
+}
+
+// create a new Arena allocating blocks of size nodes
+func New[N any](size int) *Arena[N] {
+	if size <= 0 {
+		size = DefaultBlockSize
+	}
+	return &Arena[N]{size: size}
+}
+
+// return a pointer to a new zero node
+func (a *Arena[N]) Alloc() *N {
+	if len(a.block) == 0 {
+		a.block = make([]N, a.size)
+	}
+	n := &a.block[0]
+	a.block = a.block[1:]
+	return n
+}
+
+// drop the current block; the owner has to drop its nodes as well
+func (a *Arena[N]) Free() {
+	a.block = nil
+}
+
+// number of nodes per block
+func (a *Arena[N]) BlockSize() int {
+	return a.size
+}
@@ -0,0 +1,28 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+/* Benchmarks are run with testing.Benchmark from the cli instead of
+ * "go test -bench", so they can be compared side by side in one table
+ * and the results can be reused (e.g. written as CSV).
+ */
+
+// named benchmark
+type Case struct {
+	Name string
+	Fn   func(b *testing.B)
+}
+
+// run all cases and write one line per case to w
+func Run(w io.Writer, cases []Case) {
+	for _, c := range cases {
+		res := testing.Benchmark(c.Fn)
+		fmt.Fprintf(w, "%-32s %10d %12.1f ns/op %8d B/op %6d allocs/op\n",
+			c.Name, res.N, float64(res.T.Nanoseconds())/float64(res.N),
+			res.AllocedBytesPerOp(), res.AllocsPerOp())
+	}
+}
@@ -0,0 +1,51 @@
+package bench
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/conclist"
+)
+
+// slice guarded by a single mutex, the baseline of ConcLists
+type mutexSlice[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (s *mutexSlice[T]) Append(item T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = append(s.items, item)
+	return len(s.items) - 1
+}
+
+// append from all procs at once, lock-striped list vs mutexed slice
+func ConcLists() []Case {
+	return []Case{
+		{
+			Name: "conclist/striped/append-parallel",
+			Fn: func(b *testing.B) {
+				l := conclist.New[int]()
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						l.Append(i)
+					}
+				})
+			},
+		},
+		{
+			Name: "conclist/mutex-slice/append-parallel",
+			Fn: func(b *testing.B) {
+				var s mutexSlice[int]
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						s.Append(i)
+					}
+				})
+			},
+		},
+	}
+}
@@ -0,0 +1,34 @@
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/growth"
+	"github.com/juli-99/hka-modell_basierte_software/stack"
+)
+
+// push n items onto a slice stack, per growth policy and size
+func Growth() []Case {
+	var cases []Case
+	for _, n := range []int{1024, 65536} {
+		for _, policy := range []growth.Policy{growth.Double, growth.Append, growth.Factor125, growth.Exact} {
+			if policy == growth.Exact && n > 1024 {
+				continue // quadratic, would take minutes
+			}
+			cases = append(cases, Case{
+				Name: fmt.Sprintf("stack/growth-%s/push-%d", policy, n),
+				Fn: func(b *testing.B) {
+					b.ReportAllocs()
+					for b.Loop() {
+						s := stack.NewWithGrowth[int](policy)
+						for i := range n {
+							s.Push(i)
+						}
+					}
+				},
+			})
+		}
+	}
+	return cases
+}
@@ -0,0 +1,38 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+// submit items to a pool and receive their results
+func Pools() []Case {
+	return []Case{
+		{
+			Name: "pool/submit-result",
+			Fn: func(b *testing.B) {
+				p := pool.New(1, func(n int) int { return n + 1 })
+				b.ReportAllocs()
+				for i := 0; b.Loop(); i++ {
+					p.Submit(i)
+					<-p.Results()
+				}
+				p.Close()
+			},
+		},
+		{
+			Name: "pool/submit-result-middleware",
+			Fn: func(b *testing.B) {
+				p := pool.New(1, func(n int) int { return n + 1 })
+				p.Use(pool.Recover[int, int](), pool.Retry[int, int](2))
+				b.ReportAllocs()
+				for i := 0; b.Loop(); i++ {
+					p.Submit(i)
+					<-p.Results()
+				}
+				p.Close()
+			},
+		},
+	}
+}
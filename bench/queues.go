@@ -0,0 +1,46 @@
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+)
+
+// queue implementations compared by Queues
+var queueImpls = []struct {
+	name   string
+	create func() queue.Queuer[int]
+}{
+	{queue.ImplSlice.String(), func() queue.Queuer[int] { return queue.NewWith[int](queue.ImplSlice) }},
+	{queue.ImplRing.String(), func() queue.Queuer[int] { return queue.NewWith[int](queue.ImplRing) }},
+	{queue.ImplLinked.String(), func() queue.Queuer[int] { return queue.NewWith[int](queue.ImplLinked) }},
+	{"linked-arena", func() queue.Queuer[int] { return queue.NewLinkedArena[int](0) }},
+	{queue.ImplTwoStack.String(), func() queue.Queuer[int] { return queue.NewWith[int](queue.ImplTwoStack) }},
+	{queue.ImplCOW.String(), func() queue.Queuer[int] { return queue.NewWith[int](queue.ImplCOW) }},
+}
+
+// add n items and take them out again, per implementation and size
+func Queues() []Case {
+	var cases []Case
+	for _, n := range []int{16, 1024, 65536} {
+		for _, impl := range queueImpls {
+			cases = append(cases, Case{
+				Name: fmt.Sprintf("queue/%s/add-next-%d", impl.name, n),
+				Fn: func(b *testing.B) {
+					b.ReportAllocs()
+					for b.Loop() {
+						q := impl.create()
+						for i := range n {
+							q.Add(i)
+						}
+						for !q.IsEmpty() {
+							q.Next()
+						}
+					}
+				},
+			})
+		}
+	}
+	return cases
+}
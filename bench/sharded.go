@@ -0,0 +1,96 @@
+package bench
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+)
+
+// queue guarded by a single mutex, the baseline of Sharded
+type mutexQueue[T any] struct {
+	mu sync.Mutex
+	q  queue.Queue[T]
+}
+
+func (m *mutexQueue[T]) Add(item T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.q.Add(item)
+}
+
+func (m *mutexQueue[T]) Next() (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.q.Next()
+}
+
+// number of shards of the sharded queue
+const shards = 16
+
+// add (and take) items from all procs at once, sharded queue vs mutexed queue;
+// every producer adds items of its own (the item is the key), so the
+// producers of the sharded queue rarely share a shard
+func Sharded() []Case {
+	return []Case{
+		{
+			Name: "queue/sharded/add-parallel",
+			Fn: func(b *testing.B) {
+				s := queue.NewSharded(shards, func(n int) int { return n })
+				var producers atomic.Int64
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					base := int(producers.Add(1)) << 32
+					for i := 0; pb.Next(); i++ {
+						s.Add(base + i)
+					}
+				})
+			},
+		},
+		{
+			Name: "queue/mutex-queue/add-parallel",
+			Fn: func(b *testing.B) {
+				var m mutexQueue[int]
+				var producers atomic.Int64
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					base := int(producers.Add(1)) << 32
+					for i := 0; pb.Next(); i++ {
+						m.Add(base + i)
+					}
+				})
+			},
+		},
+		{
+			Name: "queue/sharded/add-next-parallel",
+			Fn: func(b *testing.B) {
+				s := queue.NewSharded(shards, func(n int) int { return n })
+				var producers atomic.Int64
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					base := int(producers.Add(1)) << 32
+					for i := 0; pb.Next(); i++ {
+						s.Add(base + i)
+						s.Next()
+					}
+				})
+			},
+		},
+		{
+			Name: "queue/mutex-queue/add-next-parallel",
+			Fn: func(b *testing.B) {
+				var m mutexQueue[int]
+				var producers atomic.Int64
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					base := int(producers.Add(1)) << 32
+					for i := 0; pb.Next(); i++ {
+						m.Add(base + i)
+						m.Next()
+					}
+				})
+			},
+		},
+	}
+}
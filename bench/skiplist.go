@@ -0,0 +1,75 @@
+package bench
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/skiplist"
+)
+
+// number of keys the ordered sets are filled with
+const orderedKeys = 1 << 14
+
+// insert random keys and look them up, skip list vs sorted slice
+func SkipLists() []Case {
+	return []Case{
+		{
+			Name: "skiplist/set",
+			Fn: func(b *testing.B) {
+				r := rand.New(rand.NewPCG(1, 2))
+				s := skiplist.NewOrderedWithRand[int, struct{}](rand.New(rand.NewPCG(3, 4)))
+				b.ReportAllocs()
+				for b.Loop() {
+					if s.Len() == orderedKeys {
+						s = skiplist.NewOrderedWithRand[int, struct{}](rand.New(rand.NewPCG(3, 4)))
+					}
+					s.Set(r.Int(), struct{}{})
+				}
+			},
+		},
+		{
+			Name: "skiplist/sorted-slice/set",
+			Fn: func(b *testing.B) {
+				r := rand.New(rand.NewPCG(1, 2))
+				var s []int
+				b.ReportAllocs()
+				for b.Loop() {
+					if len(s) == orderedKeys {
+						s = s[:0]
+					}
+					key := r.Int()
+					if i, found := slices.BinarySearch(s, key); !found {
+						s = slices.Insert(s, i, key)
+					}
+				}
+			},
+		},
+		{
+			Name: "skiplist/get",
+			Fn: func(b *testing.B) {
+				s := skiplist.NewOrderedWithRand[int, struct{}](rand.New(rand.NewPCG(3, 4)))
+				for i := range orderedKeys {
+					s.Set(i, struct{}{})
+				}
+				b.ReportAllocs()
+				for i := 0; b.Loop(); i++ {
+					s.Get(i % orderedKeys)
+				}
+			},
+		},
+		{
+			Name: "skiplist/sorted-slice/get",
+			Fn: func(b *testing.B) {
+				s := make([]int, orderedKeys)
+				for i := range s {
+					s[i] = i
+				}
+				b.ReportAllocs()
+				for i := 0; b.Loop(); i++ {
+					_, _ = slices.BinarySearch(s, i%orderedKeys)
+				}
+			},
+		},
+	}
+}
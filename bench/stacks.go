@@ -0,0 +1,44 @@
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/stack"
+)
+
+// stack implementations compared by Stacks
+var stackImpls = []struct {
+	name   string
+	create func() stack.Stacker[int]
+}{
+	{"slice", func() stack.Stacker[int] { return stack.New[int]() }},
+	{"linked", func() stack.Stacker[int] { return stack.NewLinked[int]() }},
+	{"linked-arena", func() stack.Stacker[int] { return stack.NewLinkedArena[int](0) }},
+	{"persistent", func() stack.Stacker[int] { return stack.NewPersistent[int]() }},
+}
+
+// push n items and pop them again, per implementation and size
+func Stacks() []Case {
+	var cases []Case
+	for _, n := range []int{16, 1024, 65536} {
+		for _, impl := range stackImpls {
+			cases = append(cases, Case{
+				Name: fmt.Sprintf("stack/%s/push-pop-%d", impl.name, n),
+				Fn: func(b *testing.B) {
+					b.ReportAllocs()
+					for b.Loop() {
+						s := impl.create()
+						for i := range n {
+							s.Push(i)
+						}
+						for !s.IsEmpty() {
+							s.Pop()
+						}
+					}
+				},
+			})
+		}
+	}
+	return cases
+}
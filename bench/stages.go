@@ -0,0 +1,48 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+)
+
+// send n items through a chain of four single-worker stages,
+// with and without fusing them into one goroutine
+func Stages() []Case {
+	var cases []Case
+	for _, fuse := range []bool{true, false} {
+		name := "fused"
+		if !fuse {
+			name = "unfused"
+		}
+		const n = 1024
+		cases = append(cases, Case{
+			Name: fmt.Sprintf("stages/%s/4x%d", name, n),
+			Fn: func(b *testing.B) {
+				inc := func(x int) int { return x + 1 }
+				b.ReportAllocs()
+				for b.Loop() {
+					chain := pipeline.NewChain(pipeline.Map("a", inc, 1, 0))
+					if !fuse {
+						chain.NoFusion()
+					}
+					for _, name := range []string{"b", "c", "d"} {
+						chain = pipeline.Then(chain, pipeline.Map(name, inc, 1, 0))
+					}
+					in := make(chan int)
+					go func() {
+						for i := range n {
+							in <- i
+						}
+						close(in)
+					}()
+					for range chain.Run(context.Background(), in) {
+					}
+				}
+			},
+		})
+	}
+	return cases
+}
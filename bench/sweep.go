@@ -0,0 +1,95 @@
+package bench
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* A sweep runs the same synthetic workload through pools of every
+ * combination of worker count and buffer size and measures throughput
+ * and latency, so the effect of both settings can be plotted.
+ * The workload spins on the CPU for a fixed time per item instead of
+ * sleeping, so it competes for cores like a real validator does.
+ */
+
+// parameters of a sweep
+type SweepConfig struct {
+	Workers []int
+	Buffers []int
+	Items   int
+	Work    time.Duration // CPU time per item
+}
+
+// measurement of one combination
+type Point struct {
+	Workers    int
+	Buffer     int
+	Items      int
+	Elapsed    time.Duration
+	Throughput float64 // items per second
+	P50, P99   time.Duration
+}
+
+// keep the CPU busy for d
+func spin(d time.Duration) int {
+	n := 0
+	for start := time.Now(); time.Since(start) < d; n++ {
+	}
+	return n
+}
+
+// measure every combination of worker count and buffer size
+func Sweep(cfg SweepConfig) []Point {
+	var points []Point
+	for _, workers := range cfg.Workers {
+		for _, buffer := range cfg.Buffers {
+			p := pool.New(workers, func(int) int {
+				return spin(cfg.Work)
+			}, pool.WithBuffer[int](buffer))
+			start := time.Now()
+			go func() {
+				for i := range cfg.Items {
+					p.Submit(i)
+				}
+				p.Close()
+			}()
+			for range p.Results() {
+			}
+			elapsed := time.Since(start)
+			lat := p.Latency()
+			points = append(points, Point{
+				Workers:    workers,
+				Buffer:     buffer,
+				Items:      cfg.Items,
+				Elapsed:    elapsed,
+				Throughput: float64(cfg.Items) / elapsed.Seconds(),
+				P50:        lat.Percentile(50),
+				P99:        lat.Percentile(99),
+			})
+		}
+	}
+	return points
+}
+
+// write points as CSV with a header line, durations in microseconds
+func WriteCSV(w io.Writer, points []Point) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"workers", "buffer", "items", "elapsed_us", "items_per_s", "p50_us", "p99_us"})
+	for _, p := range points {
+		cw.Write([]string{
+			strconv.Itoa(p.Workers),
+			strconv.Itoa(p.Buffer),
+			strconv.Itoa(p.Items),
+			strconv.FormatInt(p.Elapsed.Microseconds(), 10),
+			strconv.FormatFloat(p.Throughput, 'f', 1, 64),
+			strconv.FormatInt(p.P50.Microseconds(), 10),
+			strconv.FormatInt(p.P99.Microseconds(), 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/bench"
+)
+
+// benchmark suites selectable with -suite
+var suites = map[string]func() []bench.Case{
+	"stacks":   bench.Stacks,
+	"queues":   bench.Queues,
+	"pools":    bench.Pools,
+	"growth":   bench.Growth,
+	"conclist": bench.ConcLists,
+	"skiplist": bench.SkipLists,
+	"sharded":  bench.Sharded,
+	"stages":   bench.Stages,
+}
+
+// run a benchmark suite and print the results,
+// or sweep pool settings with -sweep and write CSV
+func benchCmd(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	suite := fs.String("suite", "stacks", "benchmark suite to run")
+	sweep := fs.Bool("sweep", false, "sweep worker counts and buffer sizes of a pool instead of running a suite")
+	workers := fs.String("workers", "1,2,4,8", "worker counts of the sweep")
+	buffers := fs.String("buffers", "0,16,256", "buffer sizes of the sweep")
+	items := fs.Int("items", 2000, "items per sweep point")
+	work := fs.Duration("work", 50*time.Microsecond, "CPU time per item of the sweep")
+	csv_path := fs.String("csv", "", "write the sweep to this CSV file instead of stdout")
+	fs.Parse(args)
+
+	if !*sweep {
+		cases, ok := suites[*suite]
+		if !ok {
+			return fmt.Errorf("unknown benchmark suite %q", *suite)
+		}
+		bench.Run(os.Stdout, cases())
+		return nil
+	}
+
+	cfg := bench.SweepConfig{Items: *items, Work: *work}
+	var err error
+	if cfg.Workers, err = parseInts(*workers); err != nil {
+		return err
+	}
+	if cfg.Buffers, err = parseInts(*buffers); err != nil {
+		return err
+	}
+	out := os.Stdout
+	if *csv_path != "" {
+		f, err := os.Create(*csv_path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return bench.WriteCSV(out, bench.Sweep(cfg))
+}
+
+// parse a comma separated list of ints
+func parseInts(s string) ([]int, error) {
+	var ns []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	return ns, nil
+}
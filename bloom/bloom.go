@@ -0,0 +1,88 @@
+package bloom
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"math/bits"
+)
+
+/* A Bloom filter answers "was this key added before?" in constant memory:
+ * every key sets k bits of a bit array, a key whose k bits are all set was
+ * probably added, a key with a cleared bit was certainly not. The answer
+ * "probably" is wrong with the false-positive rate chosen at creation,
+ * provided no more than the expected number of keys are added.
+ * The k bit positions are derived from a single 64 bit hash by double
+ * hashing (Kirsch, Mitzenmacher), so the key type only needs one hash
+ * function and the filter is generic over any key.
+ */
+
+// generic Bloom filter structure
+type Filter[K any] struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    int    // bits per key
+	hash func(K) uint64
+}
+
+// error returned for a false-positive rate outside (0, 1)
+var ErrRate = errors.New("bloom: false-positive rate must lie between 0 and 1")
+
+// create a new Filter for about n keys with false-positive rate p,
+// fails with ErrRate unless 0 < p < 1
+func New[K any](n int, p float64, hash func(K) uint64) (*Filter[K], error) {
+	if !(p > 0 && p < 1) {
+		return nil, fmt.Errorf("%w: %g", ErrRate, p)
+	}
+	n = max(n, 1)
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := max(int(math.Round(float64(m)/float64(n)*math.Ln2)), 1)
+	return &Filter[K]{bits: make([]uint64, (m+63)/64), m: m, k: k, hash: hash}, nil
+}
+
+// create a new Filter for comparable keys hashed with hash/maphash
+func NewComparable[K comparable](n int, p float64) (*Filter[K], error) {
+	seed := maphash.MakeSeed()
+	return New(n, p, func(key K) uint64 {
+		return maphash.Comparable(seed, key)
+	})
+}
+
+// bit positions of key
+func (f *Filter[K]) positions(key K, fn func(pos uint64) bool) bool {
+	h1 := f.hash(key)
+	h2 := bits.RotateLeft64(h1, 32) | 1 // odd, so all positions differ for power-of-two m
+	for i := range f.k {
+		if !fn((h1 + uint64(i)*h2) % f.m) {
+			return false
+		}
+	}
+	return true
+}
+
+// add key, reports whether it was probably added before
+func (f *Filter[K]) Add(key K) bool {
+	seen := true
+	f.positions(key, func(pos uint64) bool {
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			seen = false
+			f.bits[pos/64] |= 1 << (pos % 64)
+		}
+		return true
+	})
+	return seen
+}
+
+// reports whether key was probably added
+func (f *Filter[K]) Test(key K) bool {
+	return f.positions(key, func(pos uint64) bool {
+		return f.bits[pos/64]&(1<<(pos%64)) != 0
+	})
+}
+
+// number of bits and bits per key
+func (f *Filter[K]) Size() (m uint64, k int) {
+	return f.m, f.k
+}
@@ -0,0 +1,157 @@
+package bus
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+)
+
+/* A bus decouples the producers of events (e.g. the pipeline reporting
+ * results) from their consumers (logging, metrics, a TUI): publishers do
+ * not know who listens, subscribers come and go at any time.
+ * The bus is generic over the event type, so every subscriber receives
+ * typed events without type switches.
+ * Every subscription has its own buffer. Publish never blocks: an event
+ * that does not fit into the buffer of a slow subscriber is dropped for
+ * that subscriber only and counted, so a stuck consumer can not stall
+ * the pipeline.
+ * A subscriber that must not lose any event (e.g. replaying a trace) uses
+ * SubscribeAll instead: its events wait in an unbounded queue, so it
+ * costs memory instead of events, and Publish still does not block.
+ */
+
+// generic publish/subscribe structure, safe for concurrent use
+type Bus[E any] struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription[E]]struct{}
+	closed bool
+}
+
+// single subscriber of a bus
+type Subscription[E any] struct {
+	bus     *Bus[E]
+	ch      chan E
+	backlog *queue.Blocking[E] // events not yet received, nil for a buffered subscription
+	dropped atomic.Int64
+}
+
+// create a new Bus
+func New[E any]() *Bus[E] {
+	return &Bus[E]{subs: make(map[*Subscription[E]]struct{})}
+}
+
+// subscribe to all events published from now on, buffering up to buffer
+// events; the channel of the subscription is closed by Unsubscribe or Close
+func (b *Bus[E]) Subscribe(buffer int) *Subscription[E] {
+	s := &Subscription[E]{bus: b, ch: make(chan E, buffer)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(s.ch)
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// subscribe to all events published from now on without dropping any;
+// after Unsubscribe or Close the events still queued are delivered before
+// the channel is closed, so the channel has to be drained
+func (b *Bus[E]) SubscribeAll() *Subscription[E] {
+	s := &Subscription[E]{bus: b, ch: make(chan E), backlog: queue.NewBlocking[E](nil)}
+	go func() {
+		defer close(s.ch)
+		for {
+			e, ok, _ := s.backlog.WaitNext()
+			if !ok {
+				return
+			}
+			s.ch <- e
+		}
+	}()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.backlog.Close()
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// subscribe fn, called for every event in a goroutine of its own
+// until the subscription ends
+func (b *Bus[E]) SubscribeFunc(buffer int, fn func(E)) *Subscription[E] {
+	s := b.Subscribe(buffer)
+	go func() {
+		for e := range s.ch {
+			fn(e)
+		}
+	}()
+	return s
+}
+
+// deliver e to every subscriber that has room for it
+func (b *Bus[E]) Publish(e E) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subs {
+		if s.backlog != nil {
+			s.backlog.Add(e)
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// number of current subscribers
+func (b *Bus[E]) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// end all subscriptions, later subscriptions are closed immediately
+func (b *Bus[E]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs {
+		s.end()
+	}
+	clear(b.subs)
+	b.closed = true
+}
+
+// channel delivering the events
+func (s *Subscription[E]) C() <-chan E {
+	return s.ch
+}
+
+// stop receiving events and close the channel, safe to call twice
+func (s *Subscription[E]) Unsubscribe() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if _, ok := s.bus.subs[s]; ok {
+		delete(s.bus.subs, s)
+		s.end()
+	}
+}
+
+// close the channel, after the queued events for SubscribeAll
+func (s *Subscription[E]) end() {
+	if s.backlog != nil {
+		s.backlog.Close()
+		return
+	}
+	close(s.ch)
+}
+
+// number of events dropped because the buffer was full
+func (s *Subscription[E]) Dropped() int64 {
+	return s.dropped.Load()
+}
@@ -0,0 +1,29 @@
+package bus
+
+import "testing"
+
+// a SubscribeAll subscription gets every event, also those published
+// before it was read and before Close
+func TestSubscribeAllKeepsEvents(t *testing.T) {
+	b := New[int]()
+	all := b.SubscribeAll()
+	buffered := b.Subscribe(1)
+	const n = 10000
+	for i := range n {
+		b.Publish(i)
+	}
+	b.Close()
+	want := 0
+	for e := range all.C() {
+		if e != want {
+			t.Fatalf("event %d, want %d", e, want)
+		}
+		want++
+	}
+	if want != n {
+		t.Fatalf("%d events, want %d", want, n)
+	}
+	if buffered.Dropped() != n-1 {
+		t.Fatalf("buffered subscription dropped %d events, want %d", buffered.Dropped(), n-1)
+	}
+}
@@ -0,0 +1,102 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+/* A codec turns values of one element type into bytes and back.
+ * The persisting subsystems only depend on this interface,
+ * so any element type can be stored as long as a codec exists for it.
+ * Because Codec is generic, encoding an int with a Codec[string]
+ * is a compile-time error instead of a runtime surprise.
+ */
+
+// error returned by the line codec for values spanning multiple lines
+var ErrMultiline = errors.New("codec: value contains a line break")
+
+// serialization interface for values of type T
+type Codec[T any] interface {
+	Encode(v T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// codec using encoding/json
+type JSONCodec[T any] struct{}
+
+// create a new JSON codec
+func JSON[T any]() JSONCodec[T] {
+	return JSONCodec[T]{}
+}
+
+func (JSONCodec[T]) Encode(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// codec using encoding/gob
+type GobCodec[T any] struct{}
+
+// create a new gob codec
+func Gob[T any]() GobCodec[T] {
+	return GobCodec[T]{}
+}
+
+func (GobCodec[T]) Encode(v T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+	return v, err
+}
+
+// codec storing each value as a single line of plain text
+type LineCodec[T any] struct {
+	format func(T) string
+	parse  func(string) (T, error)
+}
+
+// create a new line codec from a format and a parse function
+func Line[T any](format func(T) string, parse func(string) (T, error)) LineCodec[T] {
+	return LineCodec[T]{format: format, parse: parse}
+}
+
+// line codec for strings
+func String() LineCodec[string] {
+	return Line(
+		func(s string) string { return s },
+		func(s string) (string, error) { return s, nil },
+	)
+}
+
+// line codec for ints
+func Int() LineCodec[int] {
+	return Line(strconv.Itoa, strconv.Atoi)
+}
+
+func (c LineCodec[T]) Encode(v T) ([]byte, error) {
+	s := c.format(v)
+	if strings.ContainsAny(s, "\r\n") {
+		return nil, ErrMultiline
+	}
+	return []byte(s), nil
+}
+
+func (c LineCodec[T]) Decode(data []byte) (T, error) {
+	return c.parse(strings.TrimRight(string(data), "\r\n"))
+}
@@ -0,0 +1,47 @@
+package collect
+
+import (
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/bloom"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* Duplicate detection for streams too large to remember every item:
+ * every item is added to a Bloom filter, an item the filter has probably
+ * seen before is flagged. Flagged items are only probable duplicates,
+ * with the false-positive rate of the filter; an unflagged item is
+ * certainly seen for the first time.
+ */
+
+// sink flagging items that were probably seen before
+type Dups[T, R any] struct {
+	mu      sync.Mutex
+	filter  *bloom.Filter[T]
+	flagged []T
+}
+
+// create a new Duplicates sink recording seen items in filter
+func Duplicates[T, R any](filter *bloom.Filter[T]) *Dups[T, R] {
+	return &Dups[T, R]{filter: filter}
+}
+
+func (d *Dups[T, R]) Put(res pool.Result[T, R]) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.filter.Add(res.Item) {
+		d.flagged = append(d.flagged, res.Item)
+	}
+	return nil
+}
+
+func (d *Dups[T, R]) Close() error {
+	return nil
+}
+
+// copy of the flagged items in the order they arrived
+func (d *Dups[T, R]) Flagged() []T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]T(nil), d.flagged...)
+}
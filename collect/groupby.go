@@ -0,0 +1,58 @@
+package collect
+
+import (
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* Collectors are sinks keeping (some of) the results themselves instead
+ * of a single reduced value, so the final report can show them.
+ * They are safe for concurrent use and are read after Run returned.
+ */
+
+// sink bucketing the items of all results by key
+type Groups[T, R any, K comparable] struct {
+	mu     sync.Mutex
+	key    func(T) K
+	groups map[K][]T
+}
+
+// create a new GroupBy sink putting every item into the bucket of key(item)
+func GroupBy[T, R any, K comparable](key func(T) K) *Groups[T, R, K] {
+	return &Groups[T, R, K]{key: key, groups: make(map[K][]T)}
+}
+
+func (g *Groups[T, R, K]) Put(res pool.Result[T, R]) error {
+	k := g.key(res.Item)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.groups[k] = append(g.groups[k], res.Item)
+	return nil
+}
+
+func (g *Groups[T, R, K]) Close() error {
+	return nil
+}
+
+// copy of the items per key, in the order they arrived
+func (g *Groups[T, R, K]) Groups() map[K][]T {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	groups := make(map[K][]T, len(g.groups))
+	for k, items := range g.groups {
+		groups[k] = append([]T(nil), items...)
+	}
+	return groups
+}
+
+// number of items per key
+func (g *Groups[T, R, K]) Counts() map[K]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	counts := make(map[K]int, len(g.groups))
+	for k, items := range g.groups {
+		counts[k] = len(items)
+	}
+	return counts
+}
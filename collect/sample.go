@@ -0,0 +1,62 @@
+package collect
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* Reservoir sampling (algorithm R) keeps a uniform random sample of n
+ * items from a stream of unknown length in O(n) memory: the first n items
+ * fill the reservoir, afterwards the i-th item replaces a random slot with
+ * probability n/i. Every item seen so far is then in the sample with the
+ * same probability. With the same seed and the same order of results the
+ * sample is the same.
+ */
+
+// sink keeping a uniform random sample of the items
+type Reservoir[T, R any] struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	items []T
+	n     int
+	seen  int
+}
+
+// create a new Sample sink keeping n items, chosen by a generator seeded with seed
+func Sample[T, R any](n int, seed uint64) *Reservoir[T, R] {
+	return &Reservoir[T, R]{rng: rand.New(rand.NewPCG(seed, seed)), n: n}
+}
+
+func (r *Reservoir[T, R]) Put(res pool.Result[T, R]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seen++
+	if len(r.items) < r.n {
+		r.items = append(r.items, res.Item)
+		return nil
+	}
+	if i := r.rng.IntN(r.seen); i < r.n {
+		r.items[i] = res.Item
+	}
+	return nil
+}
+
+func (r *Reservoir[T, R]) Close() error {
+	return nil
+}
+
+// copy of the sampled items
+func (r *Reservoir[T, R]) Items() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]T(nil), r.items...)
+}
+
+// number of items the sample was drawn from
+func (r *Reservoir[T, R]) Seen() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.seen
+}
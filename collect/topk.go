@@ -0,0 +1,62 @@
+package collect
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/pqueue"
+)
+
+/* TopK keeps the k greatest items seen according to less. The kept
+ * items are stored in a priority queue whose top is the smallest of them,
+ * so a new item only has to be compared with that one and, if it is
+ * greater, replaces it in O(log k). Memory stays O(k) however many
+ * results pass through.
+ */
+
+// sink keeping the k greatest items
+type Top[T, R any] struct {
+	mu   sync.Mutex
+	k    int
+	less func(a, b T) bool
+	best *pqueue.PQueue[T]
+}
+
+// create a new TopK sink keeping the k greatest items according to less
+func TopK[T, R any](k int, less func(a, b T) bool) *Top[T, R] {
+	return &Top[T, R]{k: k, less: less, best: pqueue.New(less)}
+}
+
+func (t *Top[T, R]) Put(res pool.Result[T, R]) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.best.Len() < t.k {
+		t.best.Push(res.Item)
+		return nil
+	}
+	if smallest, ok := t.best.Peek(); ok && t.less(smallest, res.Item) {
+		t.best.Pop()
+		t.best.Push(res.Item)
+	}
+	return nil
+}
+
+func (t *Top[T, R]) Close() error {
+	return nil
+}
+
+// kept items, greatest first
+func (t *Top[T, R]) Items() []T {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	items := make([]T, 0, t.best.Len())
+	for item, ok := t.best.Pop(); ok; item, ok = t.best.Pop() {
+		items = append(items, item)
+	}
+	for _, item := range items {
+		t.best.Push(item)
+	}
+	slices.Reverse(items)
+	return items
+}
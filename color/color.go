@@ -0,0 +1,68 @@
+package color
+
+import (
+	"fmt"
+	"os"
+)
+
+/* ANSI colors make the interleaved worker output easier to follow:
+ * every worker id gets a color of its own, valid results are green and
+ * invalid ones red. Colors are only used when the output is a terminal
+ * and NO_COLOR (https://no-color.org) is not set, so redirected output
+ * stays free of escape sequences.
+ */
+
+// escape sequences
+const (
+	reset = "\x1b[0m"
+	red   = "\x1b[31m"
+	green = "\x1b[32m"
+)
+
+// colors cycled through by worker id, without red and green
+var workerColors = []string{"\x1b[33m", "\x1b[34m", "\x1b[35m", "\x1b[36m", "\x1b[93m", "\x1b[94m", "\x1b[95m", "\x1b[96m"}
+
+// reports whether f is a terminal and NO_COLOR is not set
+func Auto(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorizer of output, the zero value leaves everything uncolored
+type Painter struct {
+	on bool
+}
+
+// create a new Painter, coloring only if on
+func New(on bool) Painter {
+	return Painter{on: on}
+}
+
+// v in color
+func (p Painter) paint(color string, v any) string {
+	if !p.on {
+		return fmt.Sprint(v)
+	}
+	return color + fmt.Sprint(v) + reset
+}
+
+// v in green if valid, else in red
+func (p Painter) Result(v any, valid bool) string {
+	if valid {
+		return p.paint(green, v)
+	}
+	return p.paint(red, v)
+}
+
+// v in red
+func (p Painter) Error(v any) string {
+	return p.paint(red, v)
+}
+
+// worker name in the color of the worker with the given id
+func (p Painter) Worker(id int, name string) string {
+	return p.paint(workerColors[id%len(workerColors)], name)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/* compare matches the results of two runs by item, not by id: ids and
+ * order depend on scheduling, the item does not. An item may occur
+ * several times, so the valid and invalid results of every item are
+ * counted per file and an item is reported whenever its counts differ,
+ * including items that occur in one file only. Items are compared by
+ * their compact JSON, so the files may come from int or string pipelines.
+ */
+
+// valid and invalid results of an item in one file
+type verdicts struct {
+	valid, invalid int
+}
+
+// results of a file by item, and the items in order of their first result
+type resultSet struct {
+	by    map[string]*verdicts
+	order []string
+}
+
+// compare two result files written by a file output and report every
+// item whose validity differs
+func compareCmd(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: compare expected.jsonl actual.jsonl")
+	}
+	expected, err := readResults(args[0])
+	if err != nil {
+		return err
+	}
+	actual, err := readResults(args[1])
+	if err != nil {
+		return err
+	}
+
+	differ, items := 0, 0
+	report := func(item string) {
+		items++
+		e, a := expected.by[item], actual.by[item]
+		if e == nil {
+			e = &verdicts{}
+		}
+		if a == nil {
+			a = &verdicts{}
+		}
+		if *e != *a {
+			fmt.Printf("item %s: expected %s, actual %s\n", item, e, a)
+			differ++
+		}
+	}
+	for _, item := range expected.order {
+		report(item)
+	}
+	for _, item := range actual.order {
+		if expected.by[item] == nil {
+			report(item)
+		}
+	}
+	if differ > 0 {
+		return fmt.Errorf("compare: %d of %d items differ", differ, items)
+	}
+	fmt.Printf("compare: all %d items match\n", items)
+	return nil
+}
+
+// short description like "valid", "2x invalid", "valid + invalid" or "missing"
+func (v *verdicts) String() string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		name string
+	}{{v.valid, "valid"}, {v.invalid, "invalid"}} {
+		switch {
+		case c.n == 1:
+			parts = append(parts, c.name)
+		case c.n > 1:
+			parts = append(parts, fmt.Sprintf("%dx %s", c.n, c.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "missing"
+	}
+	return strings.Join(parts, " + ")
+}
+
+// read the results of a JSON lines file
+func readResults(path string) (*resultSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	set := &resultSet{by: make(map[string]*verdicts)}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line resultLine[json.RawMessage]
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		var item bytes.Buffer
+		if err := json.Compact(&item, line.Item); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		v := set.by[item.String()]
+		if v == nil {
+			v = &verdicts{}
+			set.by[item.String()] = v
+			set.order = append(set.order, item.String())
+		}
+		if line.Valid {
+			v.valid++
+		} else {
+			v.invalid++
+		}
+	}
+	return set, scanner.Err()
+}
@@ -0,0 +1,113 @@
+package conclist
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+/* The list grows in chunks that never move: chunk k holds ChunkSize<<k
+ * slots, so an element stays where it was appended, growing never copies
+ * elements and a few dozen chunks cover any size. Append reserves its
+ * index with an atomic counter and then only locks the stripe of that
+ * index, so appenders writing to different stripes do not wait for each
+ * other; only adding a chunk takes the growth lock, and chunks are
+ * published through atomic pointers, so finding a chunk takes no lock.
+ */
+
+// number of slots of the first chunk
+const ChunkSize = 1024
+
+// number of locks the slots are striped over
+const Stripes = 64
+
+// enough chunks for every index an int can hold
+const maxChunks = 64
+
+// slots of one chunk
+type chunk[T any] struct {
+	items []T
+	set   []bool
+}
+
+// generic concurrent append-only list structure, safe for concurrent use
+type List[T any] struct {
+	n       atomic.Int64
+	chunks  [maxChunks]atomic.Pointer[chunk[T]]
+	grow    sync.Mutex
+	stripes [Stripes]sync.Mutex
+}
+
+// create a new List
+func New[T any]() *List[T] {
+	return &List[T]{}
+}
+
+// chunk and offset within it of index i
+func locate(i int) (k, offset int) {
+	k = bits.Len(uint(i/ChunkSize+1)) - 1
+	return k, i - ChunkSize*(1<<k-1)
+}
+
+// append item and return its index
+func (l *List[T]) Append(item T) int {
+	i := int(l.n.Add(1) - 1)
+	k, offset := locate(i)
+	c := l.chunk(k)
+	mu := &l.stripes[i%Stripes]
+	mu.Lock()
+	c.items[offset], c.set[offset] = item, true
+	mu.Unlock()
+	return i
+}
+
+// chunk k, added if necessary
+func (l *List[T]) chunk(k int) *chunk[T] {
+	if c := l.chunks[k].Load(); c != nil {
+		return c
+	}
+	l.grow.Lock()
+	defer l.grow.Unlock()
+	if c := l.chunks[k].Load(); c != nil {
+		return c
+	}
+	c := &chunk[T]{items: make([]T, ChunkSize<<k), set: make([]bool, ChunkSize<<k)}
+	l.chunks[k].Store(c)
+	return c
+}
+
+// element at index i, false if i was not appended (yet)
+func (l *List[T]) Get(i int) (T, bool) {
+	var zero T
+	if i < 0 || i >= l.Len() {
+		return zero, false // return default value and false if index is out of range
+	}
+	k, offset := locate(i)
+	c := l.chunks[k].Load()
+	if c == nil {
+		return zero, false // return default value and false if the append is still in progress
+	}
+	mu := &l.stripes[i%Stripes]
+	mu.Lock()
+	defer mu.Unlock()
+	if !c.set[offset] {
+		return zero, false // return default value and false if the append is still in progress
+	}
+	return c.items[offset], true
+}
+
+// number of appends started, Get may still miss the latest of them
+func (l *List[T]) Len() int {
+	return int(l.n.Load())
+}
+
+// copy of all elements appended so far, in index order
+func (l *List[T]) Snapshot() []T {
+	items := make([]T, 0, l.Len())
+	for i := range l.Len() {
+		if item, ok := l.Get(i); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
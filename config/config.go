@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+)
+
+/* A configuration describes the validation pipelines declaratively,
+ * so exercises can be handed out as a file instead of a code change.
+ * Validators are referenced by name and resolved by the caller,
+ * items are given as text and decoded with the codec of the pipeline type.
+ */
+
+// element types a pipeline can be configured with
+const (
+	TypeInt    = "int"
+	TypeString = "string"
+)
+
+// kinds of output sinks
+const (
+	OutputConsole = "console"
+	OutputFile    = "file"
+	OutputSSE     = "sse"
+)
+
+// number of workers used if a pipeline does not set one
+const DefaultWorkers = 3
+
+// whole configuration file
+type Config struct {
+	Pipelines []Pipeline `json:"pipelines"`
+}
+
+// single validation pipeline
+type Pipeline struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Workers   int      `json:"workers"`
+	Buffer    int      `json:"buffer"`
+	Queue     string   `json:"queue,omitempty"`
+	WAL       string   `json:"wal,omitempty"` // write-ahead log, unfinished items of an earlier run are processed again
+	Input     Input    `json:"input"`
+	Validator string   `json:"validator"`
+	Outputs   []Output `json:"outputs"`
+}
+
+// source of the items, exactly one field should be set
+type Input struct {
+	Values []string `json:"values,omitempty"`
+	File   string   `json:"file,omitempty"`
+	Range  *Range   `json:"range,omitempty"`
+	Listen string   `json:"listen,omitempty"` // address of an HTTP source (see source.HTTP)
+}
+
+// arithmetic sequence start, start+step, ... with count elements
+type Range struct {
+	Start int `json:"start"`
+	Step  int `json:"step"`
+	Count int `json:"count"`
+}
+
+// results an output receives, all if empty
+const (
+	WhenValid   = "valid"
+	WhenInvalid = "invalid"
+)
+
+// sink receiving the results
+type Output struct {
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"`
+	Addr string `json:"addr,omitempty"` // address serving GET /results for sse
+	When string `json:"when,omitempty"` // WhenValid or WhenInvalid, empty for all results
+}
+
+// configuration of the built-in demo
+func Default() *Config {
+	return &Config{Pipelines: []Pipeline{
+		{
+			Name:      "ints",
+			Type:      TypeInt,
+			Workers:   DefaultWorkers,
+			Input:     Input{Range: &Range{Start: 5, Step: 7, Count: 21}},
+			Validator: "even",
+			Outputs:   []Output{{Type: OutputConsole}},
+		},
+		{
+			Name:      "strings",
+			Type:      TypeString,
+			Workers:   DefaultWorkers,
+			Input:     Input{Values: []string{"Hello World", "Generics", "World Wide Web"}},
+			Validator: "contains:World",
+			Outputs:   []Output{{Type: OutputConsole}},
+		},
+	}}
+}
+
+// read and check the JSON configuration at path
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// a misspelled key is an error instead of a silently ignored setting
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	for i := range cfg.Pipelines {
+		if cfg.Pipelines[i].Workers == 0 {
+			cfg.Pipelines[i].Workers = DefaultWorkers
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// check the configuration for missing or contradicting settings
+func (c *Config) Validate() error {
+	if len(c.Pipelines) == 0 {
+		return errors.New("no pipelines configured")
+	}
+	names := make(map[string]bool)
+	for i, p := range c.Pipelines {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("pipeline %d (%s): %w", i, p.Name, err)
+		}
+		// names key the worker names, the traces and the stats
+		if names[p.Name] {
+			return fmt.Errorf("pipeline %d (%s): duplicate name", i, p.Name)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+// check a single pipeline
+func (p Pipeline) validate() error {
+	if p.Name == "" {
+		return errors.New("no name")
+	}
+	if p.Type != TypeInt && p.Type != TypeString {
+		return fmt.Errorf("unknown type %q", p.Type)
+	}
+	if p.Workers < 1 {
+		return errors.New("workers must be positive")
+	}
+	if p.Buffer < 0 {
+		return errors.New("buffer must not be negative")
+	}
+	if p.Queue != "" {
+		if _, err := queue.ParseImpl(p.Queue); err != nil {
+			return err
+		}
+	}
+	if p.Validator == "" {
+		return errors.New("no validator")
+	}
+	sources := 0
+	if p.Input.Values != nil {
+		sources++
+	}
+	if p.Input.File != "" {
+		sources++
+	}
+	if p.Input.Range != nil {
+		sources++
+	}
+	if p.Input.Listen != "" {
+		sources++
+	}
+	if sources != 1 {
+		return errors.New("input needs exactly one of values, file, range or listen")
+	}
+	for _, o := range p.Outputs {
+		switch o.Type {
+		case OutputConsole:
+		case OutputFile:
+			if o.Path == "" {
+				return errors.New("file output needs a path")
+			}
+		case OutputSSE:
+			if o.Addr == "" {
+				return errors.New("sse output needs an addr")
+			}
+		default:
+			return fmt.Errorf("unknown output %q", o.Type)
+		}
+		if o.When != "" && o.When != WhenValid && o.When != WhenInvalid {
+			return fmt.Errorf("output %s: unknown when %q", o.Type, o.When)
+		}
+	}
+	return nil
+}
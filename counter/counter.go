@@ -0,0 +1,120 @@
+package counter
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+/* Counters can be incremented from many goroutines at once without
+ * additional locking. Making them generic keeps the counted quantity
+ * typed (e.g. a Counter[time.Duration] can not be mixed up with a
+ * Counter[int]) while all of them share one atomic implementation.
+ */
+
+// signed integer types a counter can hold
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// atomic counter structure, the zero value is ready to use
+type Counter[T Number] struct {
+	v atomic.Int64
+}
+
+// create a new Counter
+func New[T Number]() *Counter[T] {
+	return &Counter[T]{}
+}
+
+// add delta and return the new value
+func (c *Counter[T]) Add(delta T) T {
+	return T(c.v.Add(int64(delta)))
+}
+
+// add one and return the new value
+func (c *Counter[T]) Inc() T {
+	return c.Add(1)
+}
+
+// return the current value
+func (c *Counter[T]) Load() T {
+	return T(c.v.Load())
+}
+
+// set the counter to zero and return the previous value
+func (c *Counter[T]) Reset() T {
+	return T(c.v.Swap(0))
+}
+
+// set of counters keyed by a label
+type Labeled[T Number] struct {
+	mu       sync.RWMutex
+	counters map[string]*Counter[T]
+}
+
+// create a new Labeled counter
+func NewLabeled[T Number]() *Labeled[T] {
+	return &Labeled[T]{counters: make(map[string]*Counter[T])}
+}
+
+// return the counter for label, creating it if necessary
+func (l *Labeled[T]) get(label string) *Counter[T] {
+	l.mu.RLock()
+	c, ok := l.counters[label]
+	l.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok = l.counters[label]; !ok {
+		c = New[T]()
+		l.counters[label] = c
+	}
+	return c
+}
+
+// add delta to the counter of label and return its new value
+func (l *Labeled[T]) Add(label string, delta T) T {
+	return l.get(label).Add(delta)
+}
+
+// add one to the counter of label and return its new value
+func (l *Labeled[T]) Inc(label string) T {
+	return l.get(label).Inc()
+}
+
+// return the value of label, zero for unknown labels
+func (l *Labeled[T]) Load(label string) T {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if c, ok := l.counters[label]; ok {
+		return c.Load()
+	}
+	return 0
+}
+
+// sorted list of all labels
+func (l *Labeled[T]) Labels() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	labels := make([]string, 0, len(l.counters))
+	for label := range l.counters {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// copy of all current values
+func (l *Labeled[T]) Snapshot() map[string]T {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	values := make(map[string]T, len(l.counters))
+	for label, c := range l.counters {
+		values[label] = c.Load()
+	}
+	return values
+}
@@ -0,0 +1,114 @@
+package delayq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/pqueue"
+)
+
+/* A delay queue hands out items only once their scheduled time has come.
+ * The items are kept in a priority queue ordered by due time (ties are
+ * broken by insertion order), so the next item to become available is
+ * always at the front. Wait sleeps until that item is due, or until an
+ * Add schedules an earlier one.
+ */
+
+// scheduled item
+type entry[T any] struct {
+	item T
+	at   time.Time
+	seq  uint64
+}
+
+// generic delay queue structure, safe for concurrent use
+type DelayQueue[T any] struct {
+	mu    sync.Mutex
+	pq    *pqueue.PQueue[entry[T]]
+	seq   uint64
+	wake  chan struct{} // closed and replaced on every Add
+	clock func() time.Time
+}
+
+// create a new DelayQueue
+func New[T any]() *DelayQueue[T] {
+	return &DelayQueue[T]{
+		pq: pqueue.New(func(a, b entry[T]) bool {
+			if a.at.Equal(b.at) {
+				return a.seq < b.seq
+			}
+			return a.at.Before(b.at)
+		}),
+		wake:  make(chan struct{}),
+		clock: time.Now,
+	}
+}
+
+// schedule item to become available at time at
+func (d *DelayQueue[T]) Add(item T, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seq++
+	d.pq.Push(entry[T]{item: item, at: at, seq: d.seq})
+	close(d.wake)
+	d.wake = make(chan struct{})
+}
+
+// schedule item to become available after delay
+func (d *DelayQueue[T]) AddAfter(item T, delay time.Duration) {
+	d.Add(item, d.clock().Add(delay))
+}
+
+// remove and return the next due item without blocking
+func (d *DelayQueue[T]) Next() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.pq.Peek()
+	if !ok || e.at.After(d.clock()) {
+		var zero T
+		return zero, false // return default value and false if no item is due
+	}
+	d.pq.Pop()
+	return e.item, true
+}
+
+// remove and return the next item, waiting until it is due or ctx is done
+func (d *DelayQueue[T]) Wait(ctx context.Context) (T, error) {
+	for {
+		d.mu.Lock()
+		e, ok := d.pq.Peek()
+		now := d.clock()
+		if ok && !e.at.After(now) {
+			d.pq.Pop()
+			d.mu.Unlock()
+			return e.item, nil
+		}
+		wake := d.wake
+		d.mu.Unlock()
+
+		var timer *time.Timer
+		var due <-chan time.Time
+		if ok {
+			timer = time.NewTimer(e.at.Sub(now))
+			due = timer.C
+		}
+		select {
+		case <-due:
+		case <-wake:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// number of scheduled items (due or not)
+func (d *DelayQueue[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pq.Len()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+)
+
+/* Shortest path through a maze read from a text file: '#' is a wall,
+ * every other cell is free, S marks the start and E the end. A breadth-
+ * first search takes the cells from a queue in the order they were
+ * reached, so every cell is first reached on a shortest path; the cell it
+ * was reached from is remembered to walk the path back from E.
+ * Usage: go run ./examples/gridbfs [maze.txt]
+ */
+
+// cell of the grid
+type cell struct {
+	row, col int
+}
+
+// read the grid and find the cells marked S and E
+func load(path string) (grid [][]byte, start, end cell, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, start, end, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		row := []byte(scanner.Text())
+		for col, c := range row {
+			switch c {
+			case 'S':
+				start = cell{len(grid), col}
+			case 'E':
+				end = cell{len(grid), col}
+			}
+		}
+		grid = append(grid, row)
+	}
+	return grid, start, end, scanner.Err()
+}
+
+// shortest path from start to end (both included), nil if end is unreachable
+func shortest(grid [][]byte, start, end cell) []cell {
+	from := map[cell]cell{start: start}
+	q := queue.New[cell]()
+	q.Add(start)
+	for c, ok := q.Next(); ok; c, ok = q.Next() {
+		if c == end {
+			path := []cell{c}
+			for c != start {
+				c = from[c]
+				path = append(path, c)
+			}
+			slices.Reverse(path)
+			return path
+		}
+		for _, n := range []cell{{c.row - 1, c.col}, {c.row + 1, c.col}, {c.row, c.col - 1}, {c.row, c.col + 1}} {
+			if n.row < 0 || n.row >= len(grid) || n.col < 0 || n.col >= len(grid[n.row]) || grid[n.row][n.col] == '#' {
+				continue
+			}
+			if _, seen := from[n]; !seen {
+				from[n] = c
+				q.Add(n)
+			}
+		}
+	}
+	return nil
+}
+
+func main() {
+	path := "examples/gridbfs/maze.txt"
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	grid, start, end, err := load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	route := shortest(grid, start, end)
+	if route == nil {
+		fmt.Println("no path from S to E")
+		return
+	}
+	for _, c := range route[1 : len(route)-1] {
+		grid[c.row][c.col] = '*'
+	}
+	for _, row := range grid {
+		fmt.Println(string(row))
+	}
+	fmt.Printf("shortest path: %d steps\n", len(route)-1)
+}
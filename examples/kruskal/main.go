@@ -0,0 +1,49 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/juli-99/hka-modell_basierte_software/unionfind"
+)
+
+/* Kruskal's algorithm builds a minimum spanning tree by going through
+ * the edges from the cheapest to the most expensive one and keeping an
+ * edge only if it connects two cities that are not connected yet. The
+ * union-find structure answers exactly that question: Union returns
+ * false for an edge that would close a cycle.
+ */
+
+// road between two cities with its length in km
+type edge struct {
+	from, to string
+	km       int
+}
+
+func main() {
+	roads := []edge{
+		{"Karlsruhe", "Stuttgart", 80},
+		{"Karlsruhe", "Mannheim", 70},
+		{"Mannheim", "Frankfurt", 85},
+		{"Karlsruhe", "Frankfurt", 145},
+		{"Stuttgart", "Munich", 230},
+		{"Stuttgart", "Mannheim", 135},
+		{"Frankfurt", "Munich", 395},
+		{"Karlsruhe", "Freiburg", 135},
+		{"Freiburg", "Stuttgart", 200},
+	}
+	slices.SortStableFunc(roads, func(a, b edge) int { return cmp.Compare(a.km, b.km) })
+
+	cities := unionfind.New[string]()
+	total := 0
+	for _, r := range roads {
+		if cities.Union(r.from, r.to) {
+			fmt.Printf("keep %s - %s (%d km)\n", r.from, r.to, r.km)
+			total += r.km
+		} else {
+			fmt.Printf("skip %s - %s (%d km), already connected\n", r.from, r.to, r.km)
+		}
+	}
+	fmt.Printf("spanning tree: %d km, %d city group(s): %v\n", total, cities.Sets(), cities.Groups())
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/internal/lincheck"
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+	"github.com/juli-99/hka-modell_basierte_software/stack"
+)
+
+// number of goroutines and operations per goroutine
+const (
+	goroutines = 4
+	ops        = 25
+)
+
+// record a concurrent history on a blocking stack and a blocking queue
+// and check both against the sequential models
+func main() {
+	s := stack.NewBlocking[int](nil)
+	var stack_history lincheck.History[int]
+	run(func(g, i int) {
+		if i%2 == 0 {
+			item := g*1000 + i
+			stack_history.Do("push", item, func() (int, bool) {
+				return 0, s.Push(item) == nil
+			})
+			return
+		}
+		stack_history.Do("pop", 0, s.Pop)
+	})
+
+	q := queue.NewBlocking[int](nil)
+	var queue_history lincheck.History[int]
+	run(func(g, i int) {
+		if i%2 == 0 {
+			item := g*1000 + i
+			queue_history.Do("add", item, func() (int, bool) {
+				return 0, q.Add(item) == nil
+			})
+			return
+		}
+		queue_history.Do("next", 0, q.Next)
+	})
+
+	fmt.Printf("stack history (%d ops) linearizable: %t\n", len(stack_history.Ops()), lincheck.Check(lincheck.StackModel[int](), stack_history.Ops()))
+	fmt.Printf("queue history (%d ops) linearizable: %t\n", len(queue_history.Ops()), lincheck.Check(lincheck.QueueModel[int](), queue_history.Ops()))
+
+	// A stack is no queue: the same history checked against the wrong model fails
+	// as soon as two pushed items were popped in LIFO order
+	renamed := stack_history.Ops()
+	for i := range renamed {
+		renamed[i].Name = map[string]string{"push": "add", "pop": "next"}[renamed[i].Name]
+	}
+	fmt.Printf("stack history as a queue linearizable: %t\n", lincheck.Check(lincheck.QueueModel[int](), renamed))
+}
+
+// call op from several goroutines at once
+func run(op func(g, i int)) {
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ops {
+				op(g, i)
+			}
+		}()
+	}
+	wg.Wait()
+}
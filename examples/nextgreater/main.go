@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/juli-99/hka-modell_basierte_software/stack"
+)
+
+/* For every number print the next greater number to its right (or -1).
+ * The monotonic stack holds the indices still waiting for their answer;
+ * pushing an index evicts all waiting indices with a smaller value,
+ * and the new value is exactly their next greater element.
+ */
+
+func main() {
+	values := []int{2, 7, 3, 5, 4, 6, 8}
+	next := make([]int, len(values))
+	for i := range next {
+		next[i] = -1
+	}
+
+	waiting := stack.NewMonotonic(func(top, item int) bool {
+		return values[top] < values[item]
+	})
+	for i := range values {
+		for _, j := range waiting.Push(i) {
+			next[j] = values[i]
+		}
+	}
+
+	for i, v := range values {
+		fmt.Printf("%d -> %d\n", v, next[i])
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// variable values used for evaluation
+type Env map[string]float64
+
+// node of the arithmetic syntax tree
+type Node interface {
+	Eval(env Env) (float64, error)
+	String() string
+}
+
+// number literal
+type Num struct {
+	Value float64
+}
+
+// variable looked up in the environment
+type Var struct {
+	Name string
+}
+
+// unary minus
+type Neg struct {
+	X Node
+}
+
+// binary operation
+type BinOp struct {
+	Op   byte
+	L, R Node
+}
+
+func (n Num) Eval(Env) (float64, error) {
+	return n.Value, nil
+}
+
+func (n Num) String() string {
+	return fmt.Sprint(n.Value)
+}
+
+func (v Var) Eval(env Env) (float64, error) {
+	value, ok := env[v.Name]
+	if !ok {
+		return 0, fmt.Errorf("undefined variable %q", v.Name)
+	}
+	return value, nil
+}
+
+func (v Var) String() string {
+	return v.Name
+}
+
+func (n Neg) Eval(env Env) (float64, error) {
+	x, err := n.X.Eval(env)
+	return -x, err
+}
+
+func (n Neg) String() string {
+	return "(-" + n.X.String() + ")"
+}
+
+func (b BinOp) Eval(env Env) (float64, error) {
+	l, err := b.L.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.R.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch b.Op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero in %v", b)
+		}
+		return l / r, nil
+	case '^':
+		return math.Pow(l, r), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", b.Op)
+}
+
+func (b BinOp) String() string {
+	return fmt.Sprintf("(%v %c %v)", b.L, b.Op, b.R)
+}
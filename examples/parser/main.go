@@ -0,0 +1,30 @@
+package main
+
+import "fmt"
+
+// parse a few expressions, print their syntax tree and evaluate them
+func main() {
+	env := Env{"x": 3, "y": 4, "rate": 0.5}
+	for _, expr := range []string{
+		"1 + 2 * 3",
+		"(1 + 2) * 3",
+		"-x ^ 2 + y",
+		"2 ^ 3 ^ 2",
+		"(x * x + y * y) ^ 0.5",
+		"rate * (100 - z)",
+		"4 / (y - 4)",
+		"(1 + 2",
+	} {
+		tree, err := Parse(expr)
+		if err != nil {
+			fmt.Printf("%-24s parse error: %v\n", expr, err)
+			continue
+		}
+		value, err := tree.Eval(env)
+		if err != nil {
+			fmt.Printf("%-24s %v  error: %v\n", expr, tree, err)
+			continue
+		}
+		fmt.Printf("%-24s %v = %g\n", expr, tree, value)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+
+	"github.com/juli-99/hka-modell_basierte_software/stack"
+)
+
+/* The parser is a shunting-yard algorithm that builds a tree instead of
+ * postfix output. Two stacks are used: operands holds finished subtrees,
+ * operators holds operators (and open parentheses) waiting for their
+ * right operand. Whenever an operator with lower precedence arrives,
+ * waiting operators are reduced: two subtrees are popped and combined.
+ * Thanks to generics both are the same stack.Stack type, but with
+ * different element types checked by the compiler.
+ */
+
+// binding strength of the binary operators
+var precedence = map[byte]int{'+': 1, '-': 1, '*': 2, '/': 2, '^': 3, '~': 4}
+
+// operator ^ binds to the right, all others to the left
+func rightAssoc(op byte) bool {
+	return op == '^' || op == '~'
+}
+
+// parse an arithmetic expression with + - * / ^, parentheses,
+// unary minus, numbers and variables into a syntax tree
+func Parse(input string) (Node, error) {
+	operands := stack.New[Node]()
+	operators := stack.New[byte]()
+
+	// combine the topmost operator with its operands
+	reduce := func() error {
+		op, _ := operators.Pop()
+		r, ok := operands.Pop()
+		if !ok {
+			return fmt.Errorf("missing operand for %q", op)
+		}
+		if op == '~' {
+			operands.Push(Neg{X: r})
+			return nil
+		}
+		l, ok := operands.Pop()
+		if !ok {
+			return fmt.Errorf("missing operand for %q", op)
+		}
+		operands.Push(BinOp{Op: op, L: l, R: r})
+		return nil
+	}
+
+	expect_operand := true
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case unicode.IsDigit(rune(c)) || c == '.':
+			j := i
+			for j < len(input) && (unicode.IsDigit(rune(input[j])) || input[j] == '.') {
+				j++
+			}
+			value, err := strconv.ParseFloat(input[i:j], 64)
+			if err != nil {
+				return nil, err
+			}
+			operands.Push(Num{Value: value})
+			expect_operand = false
+			i = j
+		case unicode.IsLetter(rune(c)) || c == '_':
+			j := i
+			for j < len(input) && (unicode.IsLetter(rune(input[j])) || unicode.IsDigit(rune(input[j])) || input[j] == '_') {
+				j++
+			}
+			operands.Push(Var{Name: input[i:j]})
+			expect_operand = false
+			i = j
+		case c == '(':
+			operators.Push(c)
+			expect_operand = true
+			i++
+		case c == ')':
+			for {
+				top, ok := operators.Peek()
+				if !ok {
+					return nil, fmt.Errorf("unbalanced ')' at %d", i)
+				}
+				if top == '(' {
+					operators.Pop()
+					break
+				}
+				if err := reduce(); err != nil {
+					return nil, err
+				}
+			}
+			expect_operand = false
+			i++
+		case precedence[c] > 0:
+			op := c
+			if expect_operand {
+				if c != '-' {
+					return nil, fmt.Errorf("unexpected %q at %d", c, i)
+				}
+				op = '~' // unary minus
+			}
+			for {
+				top, ok := operators.Peek()
+				if !ok || top == '(' || op == '~' {
+					break
+				}
+				if precedence[top] < precedence[op] || (precedence[top] == precedence[op] && rightAssoc(op)) {
+					break
+				}
+				if err := reduce(); err != nil {
+					return nil, err
+				}
+			}
+			operators.Push(op)
+			expect_operand = true
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", c, i)
+		}
+	}
+
+	for !operators.IsEmpty() {
+		if top, _ := operators.Peek(); top == '(' {
+			return nil, fmt.Errorf("unbalanced '('")
+		}
+		if err := reduce(); err != nil {
+			return nil, err
+		}
+	}
+	root, ok := operands.Pop()
+	if !ok || !operands.IsEmpty() {
+		return nil, fmt.Errorf("malformed expression %q", input)
+	}
+	return root, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+	"github.com/juli-99/hka-modell_basierte_software/validate"
+)
+
+/* The pool validates log lines against a pattern compiled once and
+ * captures their named groups into a struct, so the sink works with a
+ * typed user and an int duration instead of parsing the line again.
+ * A line that does not match, or whose duration does not fit into the
+ * field, is invalid.
+ */
+
+// named groups of a log line
+type request struct {
+	User   string
+	Path   string
+	Millis int `regex:"ms"`
+}
+
+func main() {
+	lines := source.Slice([]string{
+		"alice GET /index.html 12ms",
+		"bob GET /api/items 250ms",
+		"garbage",
+		"carol GET /api/items 99999999999999999999ms",
+	})
+
+	re, err := validate.NewRegex(`^(?P<User>\w+) GET (?P<Path>\S+) (?P<ms>\d+)ms$`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	capture, err := validate.Capture[request](re)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	p := pipeline.New(pool.New(2, capture))
+	p.Sink(pipeline.SinkFunc[string, validate.Match[request]](func(res pool.Result[string, validate.Match[request]]) error {
+		if !res.Value.Valid {
+			fmt.Printf("invalid: %q\n", res.Item)
+			return nil
+		}
+		r := res.Value.Captures
+		fmt.Printf("user %s requested %s in %d ms (slow: %t)\n", r.User, r.Path, r.Millis, r.Millis > 100)
+		return nil
+	}))
+	if err := p.From(context.Background(), lines); err != nil {
+		fmt.Println(err)
+	}
+}
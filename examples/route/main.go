@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+)
+
+/* The even numbers are routed into a second pipeline with two workers
+ * of its own, which checks them for divisibility by 4; the odd numbers
+ * are only printed. Run returns once both branches are done.
+ */
+
+func main() {
+	q := queue.New[int]()
+	for i := 1; i <= 10; i++ {
+		q.Add(i)
+	}
+
+	by4 := pipeline.New(pool.New(2, func(n int) bool { return n%4 == 0 }))
+	by4.Sink(pipeline.Writer(os.Stdout, func(res pool.Result[int, bool]) string {
+		return fmt.Sprintf("even %d: divisible by 4: %t", res.Item, res.Value)
+	}))
+	odd := pipeline.Writer(os.Stdout, func(res pool.Result[int, bool]) string {
+		return fmt.Sprintf("odd %d", res.Item)
+	})
+
+	even := pipeline.New(pool.New(3, func(n int) bool { return n%2 == 0 }))
+	even.Sink(pipeline.Route(func(res pool.Result[int, bool]) bool { return res.Value }, map[bool]pipeline.Sink[int, bool]{
+		true:  pipeline.Branch[int, bool](by4, 4),
+		false: odd,
+	}))
+	if err := even.Run(q.Next); err != nil {
+		fmt.Println(err)
+	}
+}
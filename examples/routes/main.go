@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juli-99/hka-modell_basierte_software/graph"
+)
+
+/* A small road map of south-west Germany: Dijkstra finds the shortest
+ * route from Karlsruhe to every other city, Prim and Kruskal the
+ * cheapest road network connecting all of them (both of the same length).
+ */
+
+func main() {
+	roads := graph.New[string, int]()
+	roads.AddEdge("Karlsruhe", "Stuttgart", 80)
+	roads.AddEdge("Karlsruhe", "Mannheim", 70)
+	roads.AddEdge("Mannheim", "Frankfurt", 85)
+	roads.AddEdge("Karlsruhe", "Frankfurt", 145)
+	roads.AddEdge("Stuttgart", "Munich", 230)
+	roads.AddEdge("Stuttgart", "Mannheim", 135)
+	roads.AddEdge("Frankfurt", "Munich", 395)
+	roads.AddEdge("Karlsruhe", "Freiburg", 135)
+	roads.AddEdge("Freiburg", "Stuttgart", 200)
+	roads.AddEdge("Freiburg", "Munich", 410)
+
+	paths, err := graph.Dijkstra(roads, "Karlsruhe")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, city := range roads.Vertices() {
+		if p, ok := paths.PathTo(city); ok && len(p.Edges) > 0 {
+			fmt.Printf("Karlsruhe -> %s: %d km via %s\n", city, p.Weight, strings.Join(p.Vertices(), ", "))
+		}
+	}
+
+	for _, mst := range []struct {
+		name string
+		tree graph.Tree[string, int]
+	}{{"prim", graph.Prim(roads)}, {"kruskal", graph.Kruskal(roads)}} {
+		t := mst.tree
+		fmt.Printf("%s: %d km:", mst.name, t.Weight)
+		for _, e := range t.Edges {
+			fmt.Printf(" %s-%s", e.From, e.To)
+		}
+		fmt.Println()
+	}
+}
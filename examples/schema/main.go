@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+	"github.com/juli-99/hka-modell_basierte_software/validate"
+)
+
+/* People are read from a JSON lines file and validated by the rules in
+ * the struct tags of person. The pool only needs the verdict, the sink
+ * asks the schema again for the violated rules of invalid items.
+ * Usage: go run ./examples/schema [people.jsonl]
+ */
+
+// person as decoded from one line of the input
+type person struct {
+	Name  string   `json:"name" validate:"required,pattern=^[A-Z]"`
+	Age   int      `json:"age" validate:"min=0,max=150"`
+	Email string   `json:"email" validate:"required,pattern=^[^@ ]+@[^@ ]+$"`
+	Tags  []string `json:"tags" validate:"max=3"`
+}
+
+func main() {
+	path := "examples/schema/people.jsonl"
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	schema, err := validate.NewSchema[person]()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	people, err := source.File(path, codec.JSON[person]())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer people.Close()
+
+	p := pipeline.New(pool.New(2, schema.Validate))
+	p.Sink(pipeline.SinkFunc[person, bool](func(res pool.Result[person, bool]) error {
+		if res.Value {
+			fmt.Printf("valid: %+v\n", res.Item)
+		} else {
+			fmt.Printf("invalid: %+v\n  %s\n", res.Item, strings.ReplaceAll(schema.Check(res.Item).Error(), "\n", "\n  "))
+		}
+		return nil
+	}))
+	if err := p.From(context.Background(), people); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+)
+
+/* Stage 1 trims the raw input lines, stage 2 parses them to ints and
+ * the pool validates the ints. Each stage changes or keeps the element
+ * type, and Then only compiles if the types of neighboring stages match:
+ * putting the int validator directly behind the trim stage is rejected
+ * by the compiler. Both stages are single-worker and unbuffered, so they
+ * are fused into one goroutine ("trim+parse").
+ */
+
+func main() {
+	lines := source.Slice([]string{" 4", "7 ", "x", " 10 "})
+
+	chain := pipeline.Then(
+		pipeline.NewChain(pipeline.Map("trim", strings.TrimSpace, 1, 0)),
+		pipeline.Map("parse", func(s string) int {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return -1
+			}
+			return n
+		}, 1, 0),
+	)
+	fmt.Println("stages:", chain)
+
+	even := pool.New(2, func(n int) bool { return n >= 0 && n%2 == 0 })
+	p := pipeline.New(even)
+	p.Sink(pipeline.SinkFunc[int, bool](func(res pool.Result[int, bool]) error {
+		fmt.Printf("item: %d even: %t\n", res.Item, res.Value)
+		return nil
+	}))
+	if err := p.From(context.Background(), pipeline.Via(lines, chain)); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+)
+
+/* The numbers 1..25 are grouped into batches of up to 10 by a Window
+ * stage and the pool validates whole batches: a batch is valid if its
+ * sum does not exceed 100. The last batch only holds the 5 remaining
+ * numbers, emitted when the input ends.
+ */
+
+func main() {
+	numbers := make([]int, 25)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+	batches := pipeline.NewChain(pipeline.Window[int](10, 100*time.Millisecond))
+
+	sum := func(batch []int) int {
+		total := 0
+		for _, n := range batch {
+			total += n
+		}
+		return total
+	}
+	p := pipeline.New(pool.New(2, func(batch []int) bool { return sum(batch) <= 100 }))
+	p.Sink(pipeline.SinkFunc[[]int, bool](func(res pool.Result[[]int, bool]) error {
+		fmt.Printf("batch %v: sum %d valid: %t\n", res.Item, sum(res.Item), res.Value)
+		return nil
+	}))
+	if err := p.From(context.Background(), pipeline.Via(source.Slice(numbers), batches)); err != nil {
+		fmt.Println(err)
+	}
+}
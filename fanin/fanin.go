@@ -0,0 +1,51 @@
+package fanin
+
+import (
+	"context"
+	"sync"
+)
+
+/* Merge combines several channels of the same element type into one.
+ * The merged channel is closed exactly once, after every input has been
+ * closed (or the context is done), so consumers can simply range over it.
+ * Items of one input keep their order, items of different inputs interleave.
+ */
+
+// merge all chs into one channel, closed after all chs are closed
+func Merge[T any](chs ...<-chan T) <-chan T {
+	return MergeCtx(context.Background(), 0, chs...)
+}
+
+// merge all chs into one channel with the given buffer size.
+// The merged channel is closed after all chs are closed or ctx is done;
+// after cancellation remaining items of the inputs are not drained.
+func MergeCtx[T any](ctx context.Context, buffer int, chs ...<-chan T) <-chan T {
+	out := make(chan T, buffer)
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+	for _, ch := range chs {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case item, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case out <- item:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
@@ -0,0 +1,99 @@
+package fanout
+
+import "sync/atomic"
+
+/* Fanout copies every item of one input channel to several output
+ * channels, e.g. to validate the same items with different validators
+ * at the same time. Each output has its own buffer and its own policy
+ * for the case that its consumer falls behind:
+ * Block waits for the consumer (slowing down all outputs),
+ * DropNewest discards the new item for that output,
+ * DropOldest discards the oldest buffered item to make room.
+ */
+
+// what to do when an output buffer is full
+type Policy int
+
+const (
+	Block Policy = iota
+	DropNewest
+	DropOldest // behaves like DropNewest for unbuffered outputs
+)
+
+// settings of one output
+type Consumer struct {
+	Buffer int
+	Policy Policy
+}
+
+// single output channel with its drop counter
+type output[T any] struct {
+	ch      chan T
+	policy  Policy
+	dropped atomic.Int64
+}
+
+// generic fan-out structure
+type Fanout[T any] struct {
+	outs []*output[T]
+}
+
+// create a new Fanout with one output per consumer and start copying from in,
+// the outputs are closed once in is closed and drained
+func New[T any](in <-chan T, consumers ...Consumer) *Fanout[T] {
+	f := &Fanout[T]{}
+	for _, c := range consumers {
+		f.outs = append(f.outs, &output[T]{ch: make(chan T, c.Buffer), policy: c.Policy})
+	}
+	go f.run(in)
+	return f
+}
+
+// copy every item to all outputs
+func (f *Fanout[T]) run(in <-chan T) {
+	for item := range in {
+		for _, o := range f.outs {
+			o.send(item)
+		}
+	}
+	for _, o := range f.outs {
+		close(o.ch)
+	}
+}
+
+// deliver item according to the policy
+func (o *output[T]) send(item T) {
+	if o.policy == Block {
+		o.ch <- item
+		return
+	}
+	select {
+	case o.ch <- item:
+		return
+	default:
+	}
+	if o.policy == DropOldest && cap(o.ch) > 0 {
+		select {
+		case <-o.ch:
+		default:
+		}
+		// the fanout goroutine is the only sender, so there is room now
+		o.ch <- item
+	}
+	o.dropped.Add(1)
+}
+
+// i-th output channel (in the order of the consumers passed to New)
+func (f *Fanout[T]) Out(i int) <-chan T {
+	return f.outs[i].ch
+}
+
+// number of outputs
+func (f *Fanout[T]) Len() int {
+	return len(f.outs)
+}
+
+// number of items dropped for the i-th output so far
+func (f *Fanout[T]) Dropped(i int) int64 {
+	return f.outs[i].dropped.Load()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/juli-99/hka-modell_basierte_software/trace"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// the trace of a seeded run of the demo pipelines matches its golden file
+func TestGoldenTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	if err := runCmd([]string{"-random", "50", "-seed", "1", "-quiet", "-trace", path}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	events, err := trace.Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trace.Golden(filepath.Join("testdata", "random-seed-1.golden"), events, *update); err != nil {
+		t.Fatal(err)
+	}
+}
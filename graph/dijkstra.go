@@ -0,0 +1,94 @@
+package graph
+
+import "github.com/juli-99/hka-modell_basierte_software/pqueue"
+
+/* Dijkstra settles the vertices in the order of their distance from the
+ * source, taking the closest unsettled one from a priority queue. Instead
+ * of decreasing the key of a queued vertex, a shorter distance is pushed
+ * as a new entry and outdated entries are skipped when they are popped,
+ * which keeps the queue a plain binary heap: O((V + E) log E).
+ * Only non-negative weights give correct distances.
+ */
+
+// shortest paths from one source to every reachable vertex
+type ShortestPaths[V comparable, W Weight] struct {
+	source V
+	dist   map[V]W
+	prev   map[V]Edge[V, W] // last edge on the shortest path to a vertex
+}
+
+// path through the graph with its total weight
+type Path[V comparable, W Weight] struct {
+	Edges  []Edge[V, W]
+	Weight W
+}
+
+// vertices of the path, starting with the source
+func (p Path[V, W]) Vertices() []V {
+	if len(p.Edges) == 0 {
+		return nil
+	}
+	vertices := []V{p.Edges[0].From}
+	for _, e := range p.Edges {
+		vertices = append(vertices, e.To)
+	}
+	return vertices
+}
+
+// vertex with its tentative distance, entry of the priority queue
+type reached[V comparable, W Weight] struct {
+	v    V
+	dist W
+}
+
+// shortest paths from source, fails with ErrNegativeWeight if an edge
+// reachable from source has a negative weight
+func Dijkstra[V comparable, W Weight](g *Graph[V, W], source V) (*ShortestPaths[V, W], error) {
+	sp := &ShortestPaths[V, W]{source: source, dist: map[V]W{source: 0}, prev: make(map[V]Edge[V, W])}
+	q := pqueue.New(func(a, b reached[V, W]) bool { return a.dist < b.dist })
+	q.Push(reached[V, W]{v: source})
+	settled := make(map[V]bool)
+	for r, ok := q.Pop(); ok; r, ok = q.Pop() {
+		if settled[r.v] {
+			continue // outdated entry
+		}
+		settled[r.v] = true
+		for _, e := range g.Edges(r.v) {
+			if e.Weight < 0 {
+				return nil, ErrNegativeWeight
+			}
+			d := r.dist + e.Weight
+			if old, ok := sp.dist[e.To]; ok && old <= d {
+				continue
+			}
+			sp.dist[e.To] = d
+			sp.prev[e.To] = e
+			q.Push(reached[V, W]{v: e.To, dist: d})
+		}
+	}
+	return sp, nil
+}
+
+// length of the shortest path to target
+func (sp *ShortestPaths[V, W]) Distance(target V) (W, bool) {
+	d, ok := sp.dist[target]
+	return d, ok // return default value and false if target is unreachable
+}
+
+// shortest path to target (empty for the source itself)
+func (sp *ShortestPaths[V, W]) PathTo(target V) (Path[V, W], bool) {
+	d, ok := sp.dist[target]
+	if !ok {
+		return Path[V, W]{}, false // return empty path and false if target is unreachable
+	}
+	var edges []Edge[V, W]
+	for v := target; v != sp.source; {
+		e := sp.prev[v]
+		edges = append(edges, e)
+		v = e.From
+	}
+	for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+	return Path[V, W]{Edges: edges, Weight: d}, true
+}
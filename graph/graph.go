@@ -0,0 +1,80 @@
+package graph
+
+import "errors"
+
+/* The graph stores its edges in adjacency lists, which suits the sparse
+ * graphs of road maps and dependency networks, and keeps the vertices in
+ * the order they were added, so all algorithms visit them (and report
+ * their results) deterministically instead of in map order.
+ * Weights are numbers, since the algorithms add them up.
+ */
+
+// types usable as edge weights
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// error of Dijkstra for a graph with a negative edge weight
+var ErrNegativeWeight = errors.New("graph: negative weight")
+
+// weighted edge from one vertex to another
+type Edge[V comparable, W Weight] struct {
+	From, To V
+	Weight   W
+}
+
+// generic weighted graph structure
+type Graph[V comparable, W Weight] struct {
+	adj      map[V][]Edge[V, W]
+	vertices []V
+	directed bool
+}
+
+// create a new undirected graph
+func New[V comparable, W Weight]() *Graph[V, W] {
+	return &Graph[V, W]{adj: make(map[V][]Edge[V, W])}
+}
+
+// create a new directed graph
+func NewDirected[V comparable, W Weight]() *Graph[V, W] {
+	g := New[V, W]()
+	g.directed = true
+	return g
+}
+
+// add vertex v, returns false if it already exists
+func (g *Graph[V, W]) AddVertex(v V) bool {
+	if _, ok := g.adj[v]; ok {
+		return false
+	}
+	g.adj[v] = []Edge[V, W]{}
+	g.vertices = append(g.vertices, v)
+	return true
+}
+
+// add an edge (in both directions if undirected), adding missing vertices
+func (g *Graph[V, W]) AddEdge(from, to V, weight W) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+	g.adj[from] = append(g.adj[from], Edge[V, W]{From: from, To: to, Weight: weight})
+	if !g.directed && from != to {
+		g.adj[to] = append(g.adj[to], Edge[V, W]{From: to, To: from, Weight: weight})
+	}
+}
+
+// all vertices in the order they were added
+func (g *Graph[V, W]) Vertices() []V {
+	return g.vertices
+}
+
+// edges leaving v in the order they were added
+func (g *Graph[V, W]) Edges(v V) []Edge[V, W] {
+	return g.adj[v]
+}
+
+// reports whether the edges have a direction
+func (g *Graph[V, W]) Directed() bool {
+	return g.directed
+}
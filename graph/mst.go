@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/juli-99/hka-modell_basierte_software/pqueue"
+	"github.com/juli-99/hka-modell_basierte_software/unionfind"
+)
+
+/* Both algorithms compute a minimum spanning forest of an undirected
+ * graph: one minimum spanning tree per connected component. Prim grows
+ * a tree from a vertex by always taking the cheapest edge leaving it,
+ * found with a priority queue of the edges at the border of the tree;
+ * Kruskal goes through all edges from the cheapest one and keeps those
+ * connecting two different trees (see unionfind). Both give a tree of the
+ * same total weight, with equal weights possibly a different one.
+ */
+
+// minimum spanning forest with its total weight
+type Tree[V comparable, W Weight] struct {
+	Edges  []Edge[V, W]
+	Weight W
+}
+
+// minimum spanning forest found by Prim, the trees are grown from the
+// vertices in the order they were added
+func Prim[V comparable, W Weight](g *Graph[V, W]) Tree[V, W] {
+	var t Tree[V, W]
+	in := make(map[V]bool)
+	border := pqueue.New(func(a, b Edge[V, W]) bool { return a.Weight < b.Weight })
+	visit := func(v V) {
+		in[v] = true
+		for _, e := range g.Edges(v) {
+			if !in[e.To] {
+				border.Push(e)
+			}
+		}
+	}
+	for _, root := range g.Vertices() {
+		if in[root] {
+			continue
+		}
+		visit(root)
+		for e, ok := border.Pop(); ok; e, ok = border.Pop() {
+			if in[e.To] {
+				continue // both ends are in the tree by now
+			}
+			t.Edges = append(t.Edges, e)
+			t.Weight += e.Weight
+			visit(e.To)
+		}
+	}
+	return t
+}
+
+// minimum spanning forest found by Kruskal
+func Kruskal[V comparable, W Weight](g *Graph[V, W]) Tree[V, W] {
+	var edges []Edge[V, W]
+	for _, v := range g.Vertices() {
+		edges = append(edges, g.Edges(v)...)
+	}
+	slices.SortStableFunc(edges, func(a, b Edge[V, W]) int { return cmp.Compare(a.Weight, b.Weight) })
+	var t Tree[V, W]
+	trees := unionfind.New[V]()
+	for _, e := range edges {
+		if trees.Union(e.From, e.To) {
+			t.Edges = append(t.Edges, e)
+			t.Weight += e.Weight
+		}
+	}
+	return t
+}
@@ -0,0 +1,96 @@
+package group
+
+import (
+	"context"
+	"sync"
+)
+
+/* A group runs functions in goroutines and waits for all of them,
+ * keeping the first error. Created with WithContext, the first error
+ * also cancels the context handed out, so the other functions can stop
+ * early. SetLimit bounds the number of functions running at once,
+ * Go then blocks until a running function returned.
+ * Same idea as golang.org/x/sync/errgroup, kept local to avoid the
+ * dependency.
+ */
+
+// group structure
+type Group struct {
+	wg     sync.WaitGroup
+	cancel context.CancelCauseFunc
+	sem    chan struct{} // nil without limit
+
+	once sync.Once
+	err  error
+}
+
+// create a group whose context is cancelled by the first error or Wait
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// limit the number of functions running at once, n < 0 removes the limit.
+// Must not be called while functions are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// run fn in a new goroutine, blocks while the limit is reached
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn)
+}
+
+// run fn in a new goroutine only if the limit is not reached,
+// reports whether it was started
+func (g *Group) TryGo(fn func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+// run fn and record its error
+func (g *Group) start(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// mark a function as returned
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+// wait for all functions and return the first error
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
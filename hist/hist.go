@@ -0,0 +1,121 @@
+package hist
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* A latency histogram with fixed, exponentially growing buckets.
+ * Recording is O(log buckets) and memory does not grow with the number of
+ * recorded durations; the price is that percentiles are only accurate to
+ * the bucket bounds (the upper bound of the bucket is reported).
+ */
+
+// upper bounds of the default buckets, 1µs up to about 33s doubling each time
+var DefaultBounds = func() []time.Duration {
+	var bounds []time.Duration
+	for d := time.Microsecond; d <= 40*time.Second; d *= 2 {
+		bounds = append(bounds, d)
+	}
+	return bounds
+}()
+
+// histogram structure, safe for concurrent use
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []time.Duration
+	counts []uint64 // one more than bounds for the overflow bucket
+	total  uint64
+	sum    time.Duration
+	max    time.Duration
+}
+
+// create a new Histogram with the DefaultBounds
+func New() *Histogram {
+	return NewWithBounds(DefaultBounds)
+}
+
+// create a new Histogram with the given ascending bucket upper bounds
+func NewWithBounds(bounds []time.Duration) *Histogram {
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// record a single duration
+func (h *Histogram) Record(d time.Duration) {
+	// binary search for the first bucket with bound >= d
+	lo, hi := 0, len(h.bounds)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if h.bounds[mid] < d {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[lo]++
+	h.total++
+	h.sum += d
+	h.max = max(h.max, d)
+}
+
+// number of recorded durations
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total
+}
+
+// mean of the recorded durations
+func (h *Histogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// upper bound of the bucket containing the p-th percentile (0 < p <= 100),
+// durations beyond the last bucket are reported as the maximum seen
+func (h *Histogram) Percentile(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(p / 100 * float64(h.total))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			if i == len(h.bounds) {
+				return h.max
+			}
+			return min(h.bounds[i], h.max)
+		}
+	}
+	return h.max
+}
+
+// short summary like "n=21 mean=1.2ms p50=1ms p95=2ms p99=4ms max=3.7ms"
+func (h *Histogram) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "n=%d mean=%v", h.Count(), h.Mean())
+	for _, p := range []float64{50, 95, 99} {
+		fmt.Fprintf(&b, " p%g=%v", p, h.Percentile(p))
+	}
+	h.mu.Lock()
+	fmt.Fprintf(&b, " max=%v", h.max)
+	h.mu.Unlock()
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+/* The message catalog is keyed by the English format strings, like
+ * gettext: call sites stay readable, and a message without a translation
+ * simply falls back to English. Translations have to use the same verbs
+ * in the same order as the English format.
+ */
+
+// language of the messages
+type Lang string
+
+const (
+	English Lang = "en"
+	German  Lang = "de"
+)
+
+// translations of the English formats per language
+var catalog = map[Lang]map[string]string{
+	English: {},
+	German: {
+		"%v: item: %v result: %v":                  "%v: Element: %v Ergebnis: %v",
+		"%v: item: %v error: %v":                   "%v: Element: %v Fehler: %v",
+		"remote: item: %v error: %v\n":             "Remote: Element: %v Fehler: %v\n",
+		"Pipeline %s:\n":                           "Pipeline %s:\n",
+		"Number of valid items: %d\n":              "Anzahl gültiger Elemente: %d\n",
+		"Sample of %d items: %v\n":                 "Stichprobe aus %d Elementen: %v\n",
+		"Probable duplicates (p=%g): %v\n":         "Wahrscheinliche Duplikate (p=%g): %v\n",
+		"Validated distinct items: %d of %d\n":     "Geprüfte verschiedene Elemente: %d von %d\n",
+		"Fed back items: %d (at most %d cycles)\n": "Zurückgeführte Elemente: %d (höchstens %d Durchläufe)\n",
+		"Greatest valid items: %v\n":               "Größte gültige Elemente: %v\n",
+		"Duplicate inputs: %d\n":                   "Doppelte Eingaben: %d\n",
+		"  %v: %d times\n":                         "  %v: %d-mal\n",
+		"Failed items: %v\n":                       "Fehlgeschlagene Elemente: %v\n",
+		"Throughput: %.1f items/s (last %v)\n":     "Durchsatz: %.1f Elemente/s (letzte %v)\n",
+		"Latency: p50=%v p95=%v p99=%v\n":          "Latenz: p50=%v p95=%v p99=%v\n",
+		"Joined pairs: %d, both valid: %d\n":       "Verbundene Paare: %d, beide gültig: %d\n",
+		"Valid items: %s\n":                        "Gültige Elemente: %s\n",
+	},
+}
+
+// supported languages, sorted
+func Langs() []Lang {
+	return slices.Sorted(maps.Keys(catalog))
+}
+
+// formatter of messages in one language
+type Printer struct {
+	messages map[string]string
+}
+
+// create a new Printer for lang
+func New(lang Lang) (*Printer, error) {
+	messages, ok := catalog[lang]
+	if !ok {
+		return nil, fmt.Errorf("i18n: unknown language %q (one of %v)", lang, Langs())
+	}
+	return &Printer{messages: messages}, nil
+}
+
+// translation of format, format itself if there is none
+func (p *Printer) lookup(format string) string {
+	if msg, ok := p.messages[format]; ok {
+		return msg
+	}
+	return format
+}
+
+// like fmt.Sprintf with the translated format
+func (p *Printer) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(p.lookup(format), args...)
+}
+
+// like fmt.Fprintf with the translated format
+func (p *Printer) Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	return fmt.Fprintf(w, p.lookup(format), args...)
+}
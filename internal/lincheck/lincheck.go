@@ -0,0 +1,143 @@
+package lincheck
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+/* A concurrent container is linearizable if every history of concurrent
+ * operations on it could also have been produced by the sequential model
+ * (a plain stack or queue), executing each operation atomically at some
+ * point between its call and its return.
+ * The History records the call and return of every operation with a
+ * logical clock, Check then searches for such an order (Wing & Gong):
+ * it repeatedly picks an operation that was called before every other
+ * remaining one returned, applies it to the model and backtracks when the
+ * model disagrees with the recorded result. Visited combinations of done
+ * operations and model state are remembered, which keeps the search
+ * feasible for histories of a few hundred operations.
+ */
+
+// single recorded operation
+type Op[T any] struct {
+	Name   string // e.g. "push", "pop"
+	Arg    T
+	Ret    T
+	Ok     bool
+	Call   int64 // logical time of the call
+	Return int64 // logical time of the return
+}
+
+// recorded history of operations, safe for concurrent use
+type History[T any] struct {
+	clock atomic.Int64
+	mu    sync.Mutex
+	ops   []Op[T]
+}
+
+// run fn as the operation name with argument arg and record it,
+// returns the result of fn
+func (h *History[T]) Do(name string, arg T, fn func() (T, bool)) (T, bool) {
+	call := h.clock.Add(1)
+	ret, ok := fn()
+	op := Op[T]{Name: name, Arg: arg, Ret: ret, Ok: ok, Call: call, Return: h.clock.Add(1)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ops = append(h.ops, op)
+	return ret, ok
+}
+
+// copy of all recorded operations
+func (h *History[T]) Ops() []Op[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Op[T](nil), h.ops...)
+}
+
+// sequential specification with states of type S
+type Model[S, T any] struct {
+	Init func() S
+	// apply op to s, false if the recorded result contradicts the model;
+	// must not modify s
+	Step func(s S, op Op[T]) (S, bool)
+}
+
+// report whether ops could have been produced by the sequential model
+func Check[S, T any](m Model[S, T], ops []Op[T]) bool {
+	done := make([]byte, (len(ops)+7)/8)
+	seen := make(map[string]bool)
+
+	var search func(s S, left int) bool
+	search = func(s S, left int) bool {
+		if left == 0 {
+			return true
+		}
+		key := string(done) + fmt.Sprint(s)
+		if seen[key] {
+			return false
+		}
+		// only operations called before the first remaining return may go next
+		first_return := int64(-1)
+		for i, op := range ops {
+			if done[i/8]&(1<<(i%8)) == 0 && (first_return < 0 || op.Return < first_return) {
+				first_return = op.Return
+			}
+		}
+		for i, op := range ops {
+			if done[i/8]&(1<<(i%8)) != 0 || op.Call > first_return {
+				continue
+			}
+			next, ok := m.Step(s, op)
+			if !ok {
+				continue
+			}
+			done[i/8] |= 1 << (i % 8)
+			if search(next, left-1) {
+				return true
+			}
+			done[i/8] &^= 1 << (i % 8)
+		}
+		seen[key] = true
+		return false
+	}
+	return search(m.Init(), len(ops))
+}
+
+// sequential stack with the operations "push" and "pop"
+func StackModel[T comparable]() Model[[]T, T] {
+	return Model[[]T, T]{
+		Init: func() []T { return nil },
+		Step: func(s []T, op Op[T]) ([]T, bool) {
+			switch op.Name {
+			case "push":
+				return append(s[:len(s):len(s)], op.Arg), true
+			case "pop":
+				if len(s) == 0 {
+					return s, !op.Ok
+				}
+				return s[:len(s)-1], op.Ok && op.Ret == s[len(s)-1]
+			}
+			return s, false
+		},
+	}
+}
+
+// sequential queue with the operations "add" and "next"
+func QueueModel[T comparable]() Model[[]T, T] {
+	return Model[[]T, T]{
+		Init: func() []T { return nil },
+		Step: func(s []T, op Op[T]) ([]T, bool) {
+			switch op.Name {
+			case "add":
+				return append(s[:len(s):len(s)], op.Arg), true
+			case "next":
+				if len(s) == 0 {
+					return s, !op.Ok
+				}
+				return s[1:], op.Ok && op.Ret == s[0]
+			}
+			return s, false
+		},
+	}
+}
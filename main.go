@@ -1,98 +1,78 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
-	"time"
-
-	"github.com/juli-99/hka-modell_basierte_software/queue"
 )
 
-const NUM_WORKERS int = 3
-const NUM_INTS int = 20
-
-/* Using generics instead of interfaces is necessary in this case
- * because the validation function can operate on arbitrary types,
- * and we don't know in advance what operations it will perform.
- * By using generics, we ensure that the item and the validation function
- * both use the same concrete type, enabling full compile-time type safety
- * without requiring a common interface. This flexibility would not be possible
- * with interfaces alone, since interfaces require predefined method sets.
+/* Exit codes let scripts tell a failed run from a run whose items
+ * were too often invalid (run -fail-threshold). Errors choose their code
+ * with an ExitCode method, every other error exits with exitError.
  */
-func worker[T any](
-	id int,
-	in <-chan T,
-	out chan<- bool,
-	validate func(T) bool,
-) {
-	for item := range in {
-		valid := validate(item)
-		fmt.Printf("worker %d: item: %v result: %t\n", id, item, valid)
-		out <- valid
-	}
-	fmt.Printf("worker %d: Finished!\n", id)
-}
 
-func main() {
-	// Create a stack for integers
-	queue_int := queue.New[int]()
-	for i := 0; i <= NUM_INTS; i++ {
-		queue_int.Add(5 + i*7)
-	}
+// exit codes of the cli
+const (
+	exitError   = 1 // a command failed
+	exitUsage   = 2 // unknown command or invalid flags (see flag.ExitOnError)
+	exitInvalid = 3 // too many invalid items
+)
 
-	// Validation function: even numbers are valid
-	validate_int := func(n int) bool {
-		return n%2 == 0
-	}
+// subcommand of the cli
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
 
-	ch_in_int := make(chan int)
-	ch_out_int := make(chan bool)
-	var num_valid_int int
+var commands = []command{
+	{"run", "run the validation pipelines (default)", runCmd},
+	{"serve", "run the validation pipelines and serve their live stats on GET /stats (serve -stats :8080, takes the flags of run)", serveCmd},
+	{"bench", "run benchmarks (bench -suite stacks|queues|pools|growth|conclist|stages|skiplist|sharded, bench -sweep -csv out.csv)", benchCmd},
+	{"worker", "serve validation to remote pools (worker serve -addr :7070)", workerCmd},
+	{"replay", "replay a recorded trace (replay -speed 2 trace.jsonl)", replayCmd},
+	{"model", "export a Promela model of the pipelines (model export -o model.pml)", modelCmd},
+	{"compare", "report items whose validity differs between two result files (compare old.jsonl new.jsonl)", compareCmd},
+}
 
-	// Start workers
-	for i := 1; i <= NUM_WORKERS; i++ {
-		go worker(i, ch_in_int, ch_out_int, validate_int)
+// print the available subcommands
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.usage)
 	}
+}
 
-	for !queue_int.IsEmpty() {
-		item, _ := queue_int.Next()
-		ch_in_int <- item
-		if <-ch_out_int {
-			num_valid_int++
-		}
+func main() {
+	args := os.Args[1:]
+	// Without a subcommand (or with flags only) the pipelines are run
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		args = append([]string{"run"}, args...)
 	}
-	close(ch_in_int)
-	fmt.Printf("Number of valid items: %d\n", num_valid_int)
-
-	time.Sleep(2 * time.Second) // sleep for 2 seconds
-
-	// Create a stack for strings
-	queue_str := queue.New[string]()
-	queue_str.Add("Hello World")
-	queue_str.Add("Generics")
-	queue_str.Add("World Wide Web")
-
-	// Validation function: string contains World
-	validate_str := func(s string) bool {
-		return strings.Contains(s, "World")
+	if args[0] == "help" {
+		usage()
+		return
 	}
-
-	ch_in_str := make(chan string)
-	ch_out_str := make(chan bool)
-	var num_valid_str int
-
-	// Start workers
-	for i := 1; i <= NUM_WORKERS; i++ {
-		go worker(i+10, ch_in_str, ch_out_str, validate_str)
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(exitCode(err))
+			}
+			return
+		}
 	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+	usage()
+	os.Exit(exitUsage)
+}
 
-	for !queue_str.IsEmpty() {
-		item, _ := queue_str.Next()
-		ch_in_str <- item
-		if <-ch_out_str {
-			num_valid_str++
-		}
+// exit code for err
+func exitCode(err error) int {
+	var coded interface{ ExitCode() int }
+	if errors.As(err, &coded) {
+		return coded.ExitCode()
 	}
-	close(ch_in_str)
-	fmt.Printf("Number of valid items: %d\n", num_valid_str)
+	return exitError
 }
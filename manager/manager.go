@@ -0,0 +1,140 @@
+package manager
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* A manager hosts several logical pools (tenants), e.g. one validating
+ * ints and one validating strings, that share a global budget of workers.
+ * Every pool is started with its own number of workers, at most the whole
+ * budget, so a tenant never processes more items at once than it has
+ * workers. On top of that a worker has to get a slot from the manager
+ * before it may process an item (see pool.WithLimiter), so at most budget
+ * items are processed at once over all tenants.
+ * Free slots are not handed out first come, first served: among all
+ * tenants waiting for a slot the one with the most pending items per
+ * running worker gets it, so a tenant with a long backlog is allotted
+ * more workers than one that is nearly done, and an idle tenant does not
+ * hold back anybody.
+ */
+
+// tenant of a manager
+type tenant struct {
+	name    string
+	pending func() int
+	running int
+	waiting int
+}
+
+// load of t, pending items per running worker
+func (t *tenant) load() float64 {
+	return float64(t.pending()) / float64(t.running+1)
+}
+
+// manager structure sharing a worker budget between pools
+type Manager struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	budget  int
+	busy    int
+	tenants []*tenant
+}
+
+// create a new manager allowing budget items to be processed at once
+func New(budget int) *Manager {
+	m := &Manager{budget: max(budget, 1)}
+	m.cond = sync.NewCond(&m.mu)
+	return m
+}
+
+// create a pool named name with up to workers workers (the whole budget
+// if workers < 1) that takes its slots from the budget of m
+func Add[T, R any](m *Manager, name string, workers int, fn func(T) R, opts ...pool.Option[T]) *pool.Pool[T, R] {
+	if workers < 1 || workers > m.budget {
+		workers = m.budget
+	}
+	t := &tenant{name: name}
+	p := pool.New(workers, fn, append(opts[:len(opts):len(opts)], pool.WithLimiter[T](slots{m, t}))...)
+	t.pending = p.PendingLen
+	m.mu.Lock()
+	m.tenants = append(m.tenants, t)
+	m.mu.Unlock()
+	return p
+}
+
+// slots of the budget of m taken for t
+type slots struct {
+	m *Manager
+	t *tenant
+}
+
+func (s slots) Acquire() {
+	s.m.acquire(s.t)
+}
+
+func (s slots) Release() {
+	s.m.release(s.t)
+}
+
+// wait for a free slot allotted to t
+func (m *Manager) acquire(t *tenant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t.waiting++
+	for m.busy >= m.budget || m.neediest() != t {
+		m.cond.Wait()
+	}
+	t.waiting--
+	t.running++
+	m.busy++
+	m.cond.Broadcast() // the next tenant in line may take a remaining slot
+}
+
+// give the slot of t back
+func (m *Manager) release(t *tenant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t.running--
+	m.busy--
+	m.cond.Broadcast()
+}
+
+// waiting tenant with the highest load, nil if nobody waits
+func (m *Manager) neediest() *tenant {
+	var best *tenant
+	for _, t := range m.tenants {
+		if t.waiting > 0 && (best == nil || t.load() > best.load()) {
+			best = t
+		}
+	}
+	return best
+}
+
+// number of items that may be processed at once
+func (m *Manager) Budget() int {
+	return m.budget
+}
+
+// name and number of running workers of every tenant
+type Allotment struct {
+	Name    string
+	Running int
+	Pending int
+}
+
+// current allotment of the budget, sorted by name
+func (m *Manager) Allotments() []Allotment {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	all := make([]Allotment, 0, len(m.tenants))
+	for _, t := range m.tenants {
+		all = append(all, Allotment{Name: t.name, Running: t.running, Pending: t.pending()})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
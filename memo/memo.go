@@ -0,0 +1,102 @@
+package memo
+
+import "sync"
+
+/* A memo table remembers computed values by key. When several goroutines
+ * ask for the same missing key at the same time, only the first one
+ * computes the value, all others wait for it (singleflight): an expensive
+ * computation is never started twice, even when a burst of identical
+ * items hits all workers at once. A computation that panics is forgotten,
+ * the waiting goroutines then compute the value themselves.
+ * The table never evicts, its memory grows with the number of keys.
+ */
+
+// computation in flight
+type call[V any] struct {
+	done  chan struct{} // closed once value is set or the computation panicked
+	value V
+	ok    bool // false if the computation panicked
+}
+
+// generic memo table structure, safe for concurrent use
+type Table[K comparable, V any] struct {
+	mu       sync.Mutex
+	values   map[K]V
+	inflight map[K]*call[V]
+	computed int
+}
+
+// create a new Table
+func New[K comparable, V any]() *Table[K, V] {
+	return &Table[K, V]{values: make(map[K]V), inflight: make(map[K]*call[V])}
+}
+
+// value of key, computed by fn and remembered if it is missing;
+// concurrent calls for the same key wait for the first one's fn
+func (t *Table[K, V]) GetOrCompute(key K, fn func(K) V) V {
+	for {
+		t.mu.Lock()
+		if v, ok := t.values[key]; ok {
+			t.mu.Unlock()
+			return v
+		}
+		if c, ok := t.inflight[key]; ok {
+			t.mu.Unlock()
+			<-c.done
+			if c.ok {
+				return c.value
+			}
+			continue // the computation panicked, try again
+		}
+		c := &call[V]{done: make(chan struct{})}
+		t.inflight[key] = c
+		t.mu.Unlock()
+		t.compute(key, c, fn)
+		return c.value
+	}
+}
+
+// run fn for key and publish its value, also if fn panics
+func (t *Table[K, V]) compute(key K, c *call[V], fn func(K) V) {
+	defer func() {
+		t.mu.Lock()
+		if c.ok {
+			t.values[key] = c.value
+			t.computed++
+		}
+		delete(t.inflight, key)
+		t.mu.Unlock()
+		close(c.done)
+	}()
+	c.value = fn(key)
+	c.ok = true
+}
+
+// remembered value of key
+func (t *Table[K, V]) Get(key K) (V, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v, ok := t.values[key]
+	return v, ok // return default value and false if key is not computed yet
+}
+
+// forget the value of key, the next GetOrCompute computes it again
+func (t *Table[K, V]) Forget(key K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.values, key)
+}
+
+// number of remembered values
+func (t *Table[K, V]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.values)
+}
+
+// number of computations that finished, counting recomputations after Forget
+func (t *Table[K, V]) Computed() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.computed
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/ring"
+	"github.com/juli-99/hka-modell_basierte_software/trace"
+)
+
+/* Invariants are conditions that have to hold at any time while the
+ * pipelines run, e.g. "finished + pending == submitted" for a pool.
+ * They are registered by name and checked together, either on demand
+ * with Check or periodically with Watch. The last events observed (see
+ * Observe and Sink) are kept, so a violation is reported together with
+ * what happened right before it.
+ * A check runs concurrently with the pipeline, so it has to read
+ * everything it compares in one consistent step (like Pool.Counts),
+ * otherwise it reports violations that never happened.
+ */
+
+// number of events kept for violation reports
+const TraceSuffix = 10
+
+var (
+	mu         sync.Mutex
+	invariants = make(map[string]func() bool)
+	suffix     = ring.New[trace.Event](TraceSuffix)
+	start      = time.Now()
+)
+
+// violated invariant with the events observed before
+type Violation struct {
+	Name  string
+	Trace []trace.Event // oldest first
+}
+
+func (v *Violation) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "model: invariant %q violated", v.Name)
+	if len(v.Trace) > 0 {
+		b.WriteString(", last events:")
+	}
+	for _, e := range v.Trace {
+		fmt.Fprintf(&b, "\n  %v %s %s: item %d: %s", e.At.Round(time.Microsecond), e.Pipeline, e.WorkerName(), e.ID, e.Item)
+		if e.Err != "" {
+			fmt.Fprintf(&b, " error: %s", e.Err)
+		} else {
+			fmt.Fprintf(&b, " result: %s", e.Value)
+		}
+	}
+	return b.String()
+}
+
+// register check under name, replacing an invariant of the same name;
+// returns a function removing it again
+func Invariant(name string, check func() bool) (remove func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	invariants[name] = check
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(invariants, name)
+	}
+}
+
+// remember e for violation reports, setting its offset if it has none
+func Observe(e trace.Event) {
+	if e.At == 0 {
+		e.At = time.Since(start)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	suffix.Push(e)
+}
+
+// sink observing every result of the named pipeline
+func Sink[T, R any](name string) pipeline.Sink[T, R] {
+	return pipeline.SinkFunc[T, R](func(res pool.Result[T, R]) error {
+		Observe(trace.FromResult(name, res))
+		return nil
+	})
+}
+
+// check all invariants, returns the violated ones sorted by name
+func Check() []*Violation {
+	mu.Lock()
+	checks := make(map[string]func() bool, len(invariants))
+	for name, check := range invariants {
+		checks[name] = check
+	}
+	mu.Unlock()
+
+	var violations []*Violation
+	for name, check := range checks {
+		if !check() {
+			mu.Lock()
+			violations = append(violations, &Violation{Name: name, Trace: suffix.Snapshot()})
+			mu.Unlock()
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Name < violations[j].Name
+	})
+	return violations
+}
+
+// check all invariants every interval until ctx is done,
+// calling report for every violation
+func Watch(ctx context.Context, every time.Duration, report func(v *Violation)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, v := range Check() {
+				report(v)
+			}
+		}
+	}
+}
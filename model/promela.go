@@ -0,0 +1,171 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/juli-99/hka-modell_basierte_software/config"
+)
+
+/* The Promela export describes the channel structure of every configured
+ * pipeline so it can be checked with SPIN, e.g. for deadlock freedom:
+ * a producer submits the items into the shared input channel, every worker
+ * takes items from it and sends its results into its own output channel,
+ * one merger per worker forwards them into the merged result channel
+ * (fanin.Merge) and a collector receives all results (Pipeline.Run).
+ * Closing a channel has no counterpart in Promela, so a close is modelled
+ * by an end marker (-1): the producer sends one per worker after the last
+ * item, every worker and merger passes it on and stops.
+ * Item values do not matter for the structure, items are numbered and the
+ * validator chooses its result nondeterministically. Numbers of items,
+ * workers and the buffer sizes are taken from the configuration. run
+ * starts every pipeline with its configured workers (at most the budget
+ * of run -budget); the budget is not modelled otherwise, it only limits
+ * how many of them process an item at once.
+ */
+
+// pipeline data used by the template
+type promelaPipeline struct {
+	Name    string // as configured
+	ID      string // usable as Promela identifier
+	Items   int
+	Workers int
+	Buffer  int
+}
+
+var promelaTemplate = template.Must(template.New("promela").Parse(`/* Promela model of the validation pipelines, generated from the configuration.
+ * Check with: spin -a model.pml && cc -o pan pan.c && ./pan
+ */
+{{range .}}
+/* pipeline "{{.Name}}": {{.Items}} items, {{.Workers}} workers, buffer {{.Buffer}} */
+#define {{.ID}}_ITEMS {{.Items}}
+#define {{.ID}}_WORKERS {{.Workers}}
+
+chan {{.ID}}_in = [{{.Buffer}}] of { int };
+chan {{.ID}}_out[{{.ID}}_WORKERS] = [{{.Buffer}}] of { int, bool };
+chan {{.ID}}_merged = [0] of { int, bool };
+
+proctype {{.ID}}_produce() {
+	int i = 0;
+	do
+	:: i < {{.ID}}_ITEMS -> {{.ID}}_in ! i; i++
+	:: else -> break
+	od;
+	i = 0;
+	do
+	:: i < {{.ID}}_WORKERS -> {{.ID}}_in ! -1; i++
+	:: else -> break
+	od
+}
+
+proctype {{.ID}}_worker(byte id) {
+	int item;
+	do
+	:: {{.ID}}_in ? item ->
+		if
+		:: item < 0 -> {{.ID}}_out[id] ! -1, false; break
+		:: else ->
+			if
+			:: {{.ID}}_out[id] ! item, true
+			:: {{.ID}}_out[id] ! item, false
+			fi
+		fi
+	od
+}
+
+proctype {{.ID}}_merge(byte id) {
+	int item;
+	bool valid;
+	do
+	:: {{.ID}}_out[id] ? item, valid ->
+		if
+		:: item < 0 -> {{.ID}}_merged ! -1, false; break
+		:: else -> {{.ID}}_merged ! item, valid
+		fi
+	od
+}
+
+proctype {{.ID}}_collect() {
+	int item;
+	bool valid;
+	byte closed = 0;
+	int results = 0;
+	do
+	:: closed == {{.ID}}_WORKERS -> break
+	:: else ->
+		{{.ID}}_merged ? item, valid;
+		if
+		:: item < 0 -> closed++
+		:: else -> results++
+		fi
+	od;
+	assert(results == {{.ID}}_ITEMS)
+}
+{{end}}
+init {
+	byte w;
+	atomic {
+{{- range .}}
+		run {{.ID}}_produce();
+		run {{.ID}}_collect();
+		w = 0;
+		do
+		:: w < {{.ID}}_WORKERS -> run {{.ID}}_worker(w); run {{.ID}}_merge(w); w++
+		:: else -> break
+		od;
+{{- end}}
+	}
+}
+`))
+
+// write a Promela model of all pipelines of cfg to w
+func Promela(w io.Writer, cfg *config.Config) error {
+	var pipelines []promelaPipeline
+	for i, p := range cfg.Pipelines {
+		items, err := countItems(p.Input)
+		if err != nil {
+			return fmt.Errorf("pipeline %s: %w", p.Name, err)
+		}
+		pipelines = append(pipelines, promelaPipeline{
+			Name:    p.Name,
+			ID:      identifier(p.Name, i),
+			Items:   items,
+			Workers: p.Workers,
+			Buffer:  p.Buffer,
+		})
+	}
+	return promelaTemplate.Execute(w, pipelines)
+}
+
+// number of items a pipeline input yields
+func countItems(in config.Input) (int, error) {
+	switch {
+	case in.Range != nil:
+		return in.Range.Count, nil
+	case in.File != "":
+		data, err := os.ReadFile(in.File)
+		if err != nil {
+			return 0, err
+		}
+		return len(strings.Split(strings.TrimRight(string(data), "\n"), "\n")), nil
+	case in.Listen != "":
+		return 0, errors.New("an HTTP input has no fixed number of items")
+	}
+	return len(in.Values), nil
+}
+
+// name turned into a Promela identifier, unique by the pipeline index
+func identifier(name string, i int) string {
+	id := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+	return fmt.Sprintf("p%d_%s", i, id)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/juli-99/hka-modell_basierte_software/config"
+	"github.com/juli-99/hka-modell_basierte_software/model"
+)
+
+// export a formal model of the configured pipelines ("model export -config pipeline.json")
+func modelCmd(args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		return fmt.Errorf("usage: model export [-config file] [-format promela] [-o file]")
+	}
+	fs := flag.NewFlagSet("model export", flag.ExitOnError)
+	config_path := fs.String("config", "", "pipeline configuration (JSON), defaults to the built-in demo")
+	format := fs.String("format", "promela", "model language, only promela is supported")
+	out_path := fs.String("o", "", "output file, defaults to stdout")
+	fs.Parse(args[1:])
+
+	if *format != "promela" {
+		return fmt.Errorf("unknown model format %q", *format)
+	}
+	cfg := config.Default()
+	if *config_path != "" {
+		var err error
+		if cfg, err = config.Load(*config_path); err != nil {
+			return err
+		}
+	}
+
+	out := os.Stdout
+	if *out_path != "" {
+		f, err := os.Create(*out_path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return model.Promela(out, cfg)
+}
@@ -0,0 +1,74 @@
+package multiset
+
+import "iter"
+
+/* A multiset (bag) is a set that remembers how often each value was added.
+ * The element type has to be comparable because the counts are kept
+ * in a map keyed by the values themselves.
+ */
+
+// generic multiset structure
+type Multiset[T comparable] struct {
+	counts map[T]int
+	total  int
+}
+
+// create a new Multiset
+func New[T comparable]() *Multiset[T] {
+	return &Multiset[T]{counts: make(map[T]int)}
+}
+
+// add one occurrence of v
+func (m *Multiset[T]) Add(v T) {
+	m.AddN(v, 1)
+}
+
+// add n occurrences of v
+func (m *Multiset[T]) AddN(v T, n int) {
+	if n <= 0 {
+		return
+	}
+	m.counts[v] += n
+	m.total += n
+}
+
+// remove one occurrence of v, returns false if v was not contained
+func (m *Multiset[T]) Remove(v T) bool {
+	c, ok := m.counts[v]
+	if !ok {
+		return false
+	}
+	if c == 1 {
+		delete(m.counts, v)
+	} else {
+		m.counts[v] = c - 1
+	}
+	m.total--
+	return true
+}
+
+// number of occurrences of v
+func (m *Multiset[T]) Count(v T) int {
+	return m.counts[v]
+}
+
+// number of distinct values
+func (m *Multiset[T]) Len() int {
+	return len(m.counts)
+}
+
+// number of occurrences of all values together
+func (m *Multiset[T]) TotalLen() int {
+	return m.total
+}
+
+// iterate over the distinct values and their counts (in no particular order)
+func (m *Multiset[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for v, c := range m.counts {
+			if !yield(v, c) {
+				return
+			}
+		}
+	}
+}
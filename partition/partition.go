@@ -0,0 +1,107 @@
+package partition
+
+import (
+	"slices"
+	"sort"
+	"sync"
+)
+
+/* Consistent hashing maps keys to partitions such that adding or removing
+ * a partition only moves the keys of that partition: every partition owns
+ * several points (virtual nodes) on a ring of 64 bit hashes, and a key
+ * belongs to the partition of the first point at or after its hash.
+ * Removing a partition hands its keys to the following points, adding one
+ * takes over only the keys right before its new points. With a plain
+ * hash % n nearly every key moves when n changes.
+ * More virtual nodes spread the keys more evenly over the partitions.
+ */
+
+// default number of points per partition
+const DefaultVirtualNodes = 64
+
+// point of a partition on the ring
+type point struct {
+	hash      uint64
+	partition int
+}
+
+// consistent hash ring structure, safe for concurrent use
+type Ring struct {
+	mu     sync.RWMutex
+	vnodes int
+	points []point // sorted by hash
+}
+
+// create a new Ring with the partitions 0 to n-1 and vnodes points each
+func New(n, vnodes int) *Ring {
+	r := &Ring{vnodes: max(vnodes, 1)}
+	for p := range n {
+		r.Add(p)
+	}
+	return r
+}
+
+// splitmix64 finalizer, spreads the points of a partition over the ring
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// add partition p, nothing happens if it is already there
+func (r *Ring) Add(p int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.points, func(pt point) bool { return pt.partition == p }) {
+		return
+	}
+	for i := range r.vnodes {
+		r.points = append(r.points, point{hash: mix(uint64(p)<<20 | uint64(i)), partition: p})
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i].hash < r.points[j].hash
+	})
+}
+
+// remove partition p, its keys move to the remaining partitions
+func (r *Ring) Remove(p int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.points = slices.DeleteFunc(r.points, func(pt point) bool {
+		return pt.partition == p
+	})
+}
+
+// partition owning the key hash, -1 without partitions
+func (r *Ring) Get(hash uint64) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return -1
+	}
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	if i == len(r.points) {
+		i = 0 // wrap around
+	}
+	return r.points[i].partition
+}
+
+// sorted ids of all partitions
+func (r *Ring) Partitions() []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var parts []int
+	for _, pt := range r.points {
+		parts = append(parts, pt.partition)
+	}
+	slices.Sort(parts)
+	return slices.Compact(parts)
+}
+
+// number of partitions
+func (r *Ring) Len() int {
+	return len(r.Partitions())
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* Dot renders the structure of a pipeline as a Graphviz graph, with the
+ * real numbers of workers and buffer sizes, so a diagram in a report
+ * always matches the code: items flow from the source through the input
+ * channel(s) to the workers, their outputs are merged, and Run copies
+ * every result into the buffered channel of each sink.
+ * Render with: dot -Tsvg pipeline.dot -o pipeline.svg
+ */
+
+// write the pipeline structure as a Graphviz digraph to w
+func (p *Pipeline[T, R]) Dot(w io.Writer) error {
+	var b strings.Builder
+	workers, buffer := p.pool.Workers(), p.pool.Buffer()
+	b.WriteString("digraph pipeline {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	b.WriteString("\tsource [label=\"source\" shape=ellipse];\n")
+	b.WriteString("\tmerge [label=\"fanin.Merge\" shape=invtrapezium];\n")
+	b.WriteString("\trun [label=\"Pipeline.Run\" shape=ellipse];\n")
+	from := "source"
+	if p.chain != nil {
+		for i, s := range p.chain.Plan() {
+			fmt.Fprintf(&b, "\tstage%d [label=\"%s\\n%d workers, buffer %d\" shape=parallelogram];\n", i, s.Name, s.Workers, s.Buffer)
+			fmt.Fprintf(&b, "\t%s -> stage%d;\n", from, i)
+			from = fmt.Sprintf("stage%d", i)
+		}
+	}
+	if !p.pool.Keyed() {
+		fmt.Fprintf(&b, "\tin [label=\"input\\nbuffer %d\" shape=cds];\n", buffer)
+		fmt.Fprintf(&b, "\t%s -> in;\n", from)
+	}
+	for i := 1; i <= workers; i++ {
+		fmt.Fprintf(&b, "\tworker%d [label=\"%s\"];\n", i, p.pool.WorkerName(i))
+		if p.pool.Keyed() {
+			fmt.Fprintf(&b, "\tin%d [label=\"input %d\\nbuffer %d\" shape=cds];\n", i, i, buffer)
+			fmt.Fprintf(&b, "\t%s -> in%d [label=\"key\"];\n\tin%d -> worker%d;\n", from, i, i, i)
+		} else {
+			fmt.Fprintf(&b, "\tin -> worker%d;\n", i)
+		}
+		fmt.Fprintf(&b, "\tworker%d -> merge [label=\"buffer %d\"];\n", i, buffer)
+	}
+	b.WriteString("\tmerge -> run;\n")
+	for i, e := range p.sinks {
+		fmt.Fprintf(&b, "\tsink%d [label=\"%s\\nbuffer %d\" shape=folder];\n", i, sinkName(e.sink), e.buffer)
+		fmt.Fprintf(&b, "\trun -> sink%d;\n", i)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// short type name of a sink without pointer and type arguments
+func sinkName(s any) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", s), "*")
+	name, _, _ = strings.Cut(name, "[")
+	return name
+}
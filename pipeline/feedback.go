@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+)
+
+/* A feedback edge leads from the results back to the input of the pool:
+ * an item whose result fix turns into a new item (e.g. an invalid string
+ * normalized) is submitted again instead of being delivered, and only its
+ * final result reaches the sinks. Result.Cycle counts how often an item
+ * went around; after cycles rounds the result is delivered as it is, so
+ * a fix that never leads to a valid item can not loop forever.
+ *
+ * The pool may only be closed once no result can be fed back any more,
+ * so the submitting goroutine counts the outstanding items (submitted,
+ * result not yet handled) and waits for them after the source ended.
+ * Fed back items wait in an unbounded list, so the loop delivering the
+ * results never blocks on the submitting goroutine and vice versa.
+ */
+
+// feed results fix turns into a new item back into the pool,
+// at most cycles times per item
+func (p *Pipeline[T, R]) Feedback(fix func(res pool.Result[T, R]) (T, bool), cycles int) *Pipeline[T, R] {
+	p.fix, p.cycles = fix, cycles
+	return p
+}
+
+// item on its way back into the pool
+type fedBack[T any] struct {
+	item  T
+	cycle int
+}
+
+// state shared by the submitting goroutine and the result loop
+type loop[T any] struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	items       []fedBack[T]
+	outstanding int
+}
+
+func newLoop[T any]() *loop[T] {
+	l := &loop[T]{}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// feed res back if fix and the cycle limit allow it, otherwise count it
+// as done; reports whether res was fed back
+func (p *Pipeline[T, R]) feedBack(l *loop[T], res pool.Result[T, R]) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.cond.Broadcast()
+	if res.Cycle < p.cycles {
+		if next, ok := p.fix(res); ok {
+			l.items = append(l.items, fedBack[T]{item: next, cycle: res.Cycle + 1})
+			return true
+		}
+	}
+	l.outstanding--
+	return false
+}
+
+// submit the items of src and the fed back items until src ended and
+// no item is outstanding, or ctx is done
+func (p *Pipeline[T, R]) feed(ctx context.Context, src source.Source[T], errs chan<- error, l *loop[T]) {
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cond.Broadcast()
+	})
+	defer stop()
+	exhausted := false
+	for ctx.Err() == nil {
+		l.mu.Lock()
+		for exhausted && len(l.items) == 0 && l.outstanding > 0 && ctx.Err() == nil {
+			l.cond.Wait()
+		}
+		if len(l.items) > 0 {
+			next := l.items[0]
+			l.items = l.items[1:]
+			l.mu.Unlock()
+			if p.pool.Resubmit(ctx, next.item, next.cycle) != nil {
+				return
+			}
+			continue
+		}
+		l.mu.Unlock()
+		if exhausted {
+			return
+		}
+		item, ok, err := src.Next(ctx)
+		if err != nil {
+			errs <- err
+		}
+		if !ok || err != nil {
+			exhausted = true
+			continue
+		}
+		l.mu.Lock()
+		l.outstanding++
+		l.mu.Unlock()
+		if p.pool.SubmitBlockingContext(ctx, item) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* A join combines the results of two pipelines of different types into
+ * one stream of pairs, turning two linear pipelines into a dataflow graph
+ * with two inputs. Left and Right are sinks for the two pipelines; a
+ * result waits in the join until the result with the same key arrives
+ * from the other side, then both are passed to the summary function as a
+ * Pair. Keying by ByID pairs the results with the same pool id. The pool
+ * numbers the items in the order they are submitted, so without items fed
+ * back (see Feedback) or recovered from a log this pairs the n-th
+ * submitted item of one pipeline with the n-th of the other.
+ * Results whose partner never arrives are kept and reported by Unmatched.
+ */
+
+// results of both sides with the same key
+type Pair[TA, RA, TB, RB any] struct {
+	Left  pool.Result[TA, RA]
+	Right pool.Result[TB, RB]
+}
+
+// key pairing results by their pool id
+func ByID[T, R any](res pool.Result[T, R]) uint64 {
+	return res.ID
+}
+
+// generic join of two result streams, safe for concurrent use
+type Join[TA, RA, TB, RB any, K comparable] struct {
+	mu      sync.Mutex
+	keyA    func(pool.Result[TA, RA]) K
+	keyB    func(pool.Result[TB, RB]) K
+	left    map[K]pool.Result[TA, RA] // waiting for their right partner
+	right   map[K]pool.Result[TB, RB] // waiting for their left partner
+	summary func(Pair[TA, RA, TB, RB])
+}
+
+// create a new Join calling summary for every pair of results with the
+// same key, one call at a time
+func NewJoin[TA, RA, TB, RB any, K comparable](keyA func(pool.Result[TA, RA]) K, keyB func(pool.Result[TB, RB]) K, summary func(Pair[TA, RA, TB, RB])) *Join[TA, RA, TB, RB, K] {
+	return &Join[TA, RA, TB, RB, K]{
+		keyA:    keyA,
+		keyB:    keyB,
+		left:    make(map[K]pool.Result[TA, RA]),
+		right:   make(map[K]pool.Result[TB, RB]),
+		summary: summary,
+	}
+}
+
+// sink of the left pipeline
+func (j *Join[TA, RA, TB, RB, K]) Left() Sink[TA, RA] {
+	return SinkFunc[TA, RA](func(res pool.Result[TA, RA]) error {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		key := j.keyA(res)
+		partner, ok := j.right[key]
+		if !ok {
+			j.left[key] = res
+			return nil
+		}
+		delete(j.right, key)
+		j.summary(Pair[TA, RA, TB, RB]{Left: res, Right: partner})
+		return nil
+	})
+}
+
+// sink of the right pipeline
+func (j *Join[TA, RA, TB, RB, K]) Right() Sink[TB, RB] {
+	return SinkFunc[TB, RB](func(res pool.Result[TB, RB]) error {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		key := j.keyB(res)
+		partner, ok := j.left[key]
+		if !ok {
+			j.right[key] = res
+			return nil
+		}
+		delete(j.left, key)
+		j.summary(Pair[TA, RA, TB, RB]{Left: partner, Right: res})
+		return nil
+	})
+}
+
+// number of results of each side still waiting for a partner
+func (j *Join[TA, RA, TB, RB, K]) Unmatched() (left, right int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.left), len(j.right)
+}
@@ -0,0 +1,203 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+)
+
+/* A pipeline feeds items into a pool and delivers every result to all
+ * registered sinks. Each sink runs in its own goroutine behind its own
+ * buffered channel, so a slow sink (e.g. a file) only delays the others
+ * once its buffer is full, instead of every consumer being handled
+ * inline in one loop.
+ */
+
+// buffer size used by Sink
+const DefaultSinkBuffer = 16
+
+// receiver of results
+type Sink[T, R any] interface {
+	Put(res pool.Result[T, R]) error
+	Close() error
+}
+
+// adapter to use a function as a sink without a Close step
+type SinkFunc[T, R any] func(res pool.Result[T, R]) error
+
+func (f SinkFunc[T, R]) Put(res pool.Result[T, R]) error {
+	return f(res)
+}
+
+func (f SinkFunc[T, R]) Close() error {
+	return nil
+}
+
+// registered sink with its buffer size
+type sinkEntry[T, R any] struct {
+	sink   Sink[T, R]
+	buffer int
+}
+
+// generic pipeline structure
+type Pipeline[T, R any] struct {
+	pool  *pool.Pool[T, R]
+	sinks []sinkEntry[T, R]
+	chain *Chain[T, T] // stages before the pool, nil = none
+
+	fix    func(res pool.Result[T, R]) (T, bool) // feedback, nil = none
+	cycles int
+}
+
+// create a new Pipeline processing items with p
+func New[T, R any](p *pool.Pool[T, R]) *Pipeline[T, R] {
+	return &Pipeline[T, R]{pool: p}
+}
+
+// register s with the default buffer size
+func (p *Pipeline[T, R]) Sink(s Sink[T, R]) *Pipeline[T, R] {
+	return p.SinkBuffered(s, DefaultSinkBuffer)
+}
+
+// register s buffering up to buffer results
+func (p *Pipeline[T, R]) SinkBuffered(s Sink[T, R], buffer int) *Pipeline[T, R] {
+	p.sinks = append(p.sinks, sinkEntry[T, R]{sink: s, buffer: buffer})
+	return p
+}
+
+// send the items through the stages of c before submitting them
+// (see Via for stages changing the element type)
+func (p *Pipeline[T, R]) Through(c *Chain[T, T]) *Pipeline[T, R] {
+	p.chain = c
+	return p
+}
+
+// submit items returned by next until it reports false (e.g. queue.Next),
+// then close the pool and wait until all sinks got every result.
+// Returns the errors of all sinks, a failed sink drops further results.
+func (p *Pipeline[T, R]) Run(next func() (T, bool)) error {
+	return p.RunContext(context.Background(), next)
+}
+
+/* When the context ends before the pool is done, submitting stops and
+ * the sinks are closed with the results delivered so far, so a job whose
+ * validator blocks forever still ends with partial results. Goroutines
+ * can not be killed from outside, so a blocked validator keeps its worker;
+ * the remaining results are drained in the background and dropped.
+ * The submitting goroutine is not left behind though: it waits for the
+ * pool and the source with ctx and is joined before RunContext returns,
+ * so the caller may look at whatever the source touched (e.g. counted
+ * inputs) without racing with it. A source has to honor ctx for that.
+ */
+
+// like Run, but aborts once ctx is done and returns its error
+// together with the errors of the sinks
+func (p *Pipeline[T, R]) RunContext(ctx context.Context, next func() (T, bool)) error {
+	return p.From(ctx, source.Generate(next))
+}
+
+/* A failing source stops submitting like an aborted context does, but
+ * the items submitted so far are still processed and delivered, and the
+ * error of the source is returned together with those of the sinks.
+ */
+
+// like RunContext, but takes the items from src
+func (p *Pipeline[T, R]) From(ctx context.Context, src source.Source[T]) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.sinks))
+	chans := make([]chan pool.Result[T, R], len(p.sinks))
+	for i, e := range p.sinks {
+		chans[i] = make(chan pool.Result[T, R], e.buffer)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for res := range chans[i] {
+				if errs[i] == nil {
+					errs[i] = e.sink.Put(res)
+				}
+			}
+			errs[i] = errors.Join(errs[i], e.sink.Close())
+		}()
+	}
+
+	if p.chain != nil {
+		src = Via(src, p.chain)
+	}
+	src_err := make(chan error, 1)
+	var fb *loop[T]
+	if p.fix != nil {
+		fb = newLoop[T]()
+	}
+	fed := make(chan struct{})
+	go func() {
+		defer close(fed)
+		defer p.pool.Close()
+		if fb != nil {
+			p.feed(ctx, src, src_err, fb)
+			return
+		}
+		for ctx.Err() == nil {
+			item, ok, err := src.Next(ctx)
+			if err != nil {
+				src_err <- err
+			}
+			if !ok || err != nil {
+				return
+			}
+			if p.pool.SubmitBlockingContext(ctx, item) != nil {
+				return
+			}
+		}
+	}()
+	var abort error
+	delivered := 0
+	results := p.pool.Results()
+loop:
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				break loop
+			}
+			if fb != nil && p.feedBack(fb, res) {
+				continue
+			}
+			for _, ch := range chans {
+				ch <- res
+			}
+			delivered++
+		case <-ctx.Done():
+			abort = fmt.Errorf("pipeline: aborted after %d results: %w", delivered, ctx.Err())
+			go func() {
+				for range results {
+				}
+			}()
+			break loop
+		}
+	}
+	for _, ch := range chans {
+		close(ch)
+	}
+	wg.Wait()
+	<-fed
+	select {
+	case err := <-src_err:
+		if !errors.Is(err, ctx.Err()) {
+			abort = errors.Join(abort, fmt.Errorf("pipeline: source: %w", err))
+		}
+	default:
+	}
+	return errors.Join(abort, errors.Join(errs...))
+}
+
+// like Run, but aborts after d with an error wrapping context.DeadlineExceeded
+func (p *Pipeline[T, R]) RunWithTimeout(d time.Duration, next func() (T, bool)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return p.RunContext(ctx, next)
+}
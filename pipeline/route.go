@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+)
+
+/* Route splits the results into branches by a key, e.g. valid results
+ * to one sink and invalid ones to another. A branch is any sink; with
+ * Branch it is a whole pipeline of its own, with its own pool, workers
+ * and sinks, which processes the items routed to it while the first
+ * pipeline is still running. Closing the route closes every branch, so
+ * Run returns only after all branches are done as well.
+ */
+
+// sink passing every result to the branch of its key,
+// results without a branch are dropped
+func Route[T, R any, K comparable](key func(res pool.Result[T, R]) K, branches map[K]Sink[T, R]) Sink[T, R] {
+	return &routeSink[T, R, K]{key: key, branches: branches}
+}
+
+type routeSink[T, R any, K comparable] struct {
+	key      func(res pool.Result[T, R]) K
+	branches map[K]Sink[T, R]
+}
+
+func (s *routeSink[T, R, K]) Put(res pool.Result[T, R]) error {
+	if branch, ok := s.branches[s.key(res)]; ok {
+		return branch.Put(res)
+	}
+	return nil
+}
+
+func (s *routeSink[T, R, K]) Close() error {
+	var errs []error
+	for _, branch := range s.branches {
+		errs = append(errs, branch.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// sink feeding the items of the results into p, which runs in the
+// background until the sink is closed; buffer items are queued for p
+// before Put blocks. Close returns the error of p's run.
+func Branch[T, R, R2 any](p *Pipeline[T, R2], buffer int) Sink[T, R] {
+	s := &branchSink[T, R, R2]{items: make(chan T, buffer), done: make(chan error, 1)}
+	go func() {
+		s.done <- p.From(context.Background(), source.Chan(s.items))
+	}()
+	return s
+}
+
+type branchSink[T, R, R2 any] struct {
+	items chan T
+	done  chan error
+}
+
+func (s *branchSink[T, R, R2]) Put(res pool.Result[T, R]) error {
+	s.items <- res.Item
+	return nil
+}
+
+func (s *branchSink[T, R, R2]) Close() error {
+	close(s.items)
+	return <-s.done
+}
@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/juli-99/hka-modell_basierte_software/bus"
+	"github.com/juli-99/hka-modell_basierte_software/counter"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+// sink writing one formatted line per result to w
+func Writer[T, R any](w io.Writer, format func(res pool.Result[T, R]) string) Sink[T, R] {
+	return SinkFunc[T, R](func(res pool.Result[T, R]) error {
+		_, err := fmt.Fprintln(w, format(res))
+		return err
+	})
+}
+
+// sink writing one JSON object per result to w, closing w at the end
+func JSONLines[T, R any](w io.WriteCloser, line func(res pool.Result[T, R]) any) Sink[T, R] {
+	return &jsonSink[T, R]{w: w, enc: json.NewEncoder(w), line: line}
+}
+
+type jsonSink[T, R any] struct {
+	w    io.WriteCloser
+	enc  *json.Encoder
+	line func(res pool.Result[T, R]) any
+}
+
+func (s *jsonSink[T, R]) Put(res pool.Result[T, R]) error {
+	return s.enc.Encode(s.line(res))
+}
+
+func (s *jsonSink[T, R]) Close() error {
+	return s.w.Close()
+}
+
+// sink counting every result under the label returned for it
+func Metrics[T, R any](counts *counter.Labeled[int], label func(res pool.Result[T, R]) string) Sink[T, R] {
+	return SinkFunc[T, R](func(res pool.Result[T, R]) error {
+		counts.Inc(label(res))
+		return nil
+	})
+}
+
+// sink publishing every result on b, so any number of subscribers
+// (logging, metrics, a TUI) can follow the results; b stays open
+func Publish[T, R any](b *bus.Bus[pool.Result[T, R]]) Sink[T, R] {
+	return SinkFunc[T, R](func(res pool.Result[T, R]) error {
+		b.Publish(res)
+		return nil
+	})
+}
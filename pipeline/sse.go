@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/juli-99/hka-modell_basierte_software/bus"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* The SSE sink streams the results to any number of HTTP clients as
+ * Server-Sent Events, one event per result with the result id as event id
+ * and the JSON of line as data:
+ *   id: 7
+ *   data: {"id":7,"item":42,"valid":true}
+ * Once the pipeline is done every stream ends with an "end" event.
+ * Clients only receive results from the moment they connect, and results
+ * are published on a bus, so a slow client loses results (see bus)
+ * instead of stalling the pipeline.
+ */
+
+// results buffered per connected client
+const SSEBuffer = 256
+
+// sink streaming results to HTTP clients, serves them as http.Handler
+type SSE[T, R any] struct {
+	b    *bus.Bus[pool.Result[T, R]]
+	line func(res pool.Result[T, R]) any
+}
+
+// create a new SSE sink sending line(res) for every result
+func NewSSE[T, R any](line func(res pool.Result[T, R]) any) *SSE[T, R] {
+	return &SSE[T, R]{b: bus.New[pool.Result[T, R]](), line: line}
+}
+
+func (s *SSE[T, R]) Put(res pool.Result[T, R]) error {
+	s.b.Publish(res)
+	return nil
+}
+
+// end all streams
+func (s *SSE[T, R]) Close() error {
+	s.b.Close()
+	return nil
+}
+
+// stream the results to the client until the pipeline is done
+// or the client disconnects
+func (s *SSE[T, R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	sub := s.b.Subscribe(SSEBuffer)
+	defer sub.Unsubscribe()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case res, ok := <-sub.C():
+			if !ok {
+				fmt.Fprint(w, "event: end\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			data, err := json.Marshal(s.line(res))
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", res.ID, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
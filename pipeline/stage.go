@@ -0,0 +1,221 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/source"
+)
+
+/* Stages transform the items on their way from the source to the pool,
+ * each with its own goroutines. A stage may change the element type:
+ * Then only accepts a stage whose input type is the output type of the
+ * chain so far, so e.g. a stage parsing strings to ints can only be
+ * followed by stages taking ints, checked by the compiler.
+ *
+ * Every stage boundary costs a channel hop: a send, a receive and usually
+ * a goroutine switch. A stage with a single worker and no buffer gains
+ * nothing from running on its own, so consecutive stages of that kind are
+ * fused into one goroutine that calls their functions one after another.
+ * Fusion keeps the order and the results of the items, only the hops
+ * disappear (bench -suite stages). NoFusion turns it off, e.g. to compare.
+ *
+ * Internally a chain does not end in a channel but in an emit function
+ * called with every output item. A fused stage simply wraps the emit
+ * function of the stage after it; only a boundary that is not fused
+ * creates a channel and the goroutines of the next stage.
+ */
+
+// transformation step from A to B
+type Stage[A, B any] struct {
+	info StageInfo
+	fn   func(A) B // nil for stages keeping state across items (Window)
+	// run the stage on in and pass the outputs to emit until it reports
+	// false, returns once all workers are done
+	run func(ctx context.Context, in <-chan A, emit func(B) bool)
+}
+
+// name and concurrency of a stage
+type StageInfo struct {
+	Name    string
+	Workers int
+	Buffer  int // size of the output buffer
+}
+
+// reports whether the stage can share a goroutine with its neighbors
+func (s Stage[A, B]) fusible() bool {
+	return s.fn != nil && s.info.Workers == 1 && s.info.Buffer == 0
+}
+
+// create a new Stage applying fn with workers goroutines (at least 1)
+// and an output buffer of buffer items
+func Map[A, B any](name string, fn func(A) B, workers, buffer int) Stage[A, B] {
+	workers = max(workers, 1)
+	return Stage[A, B]{
+		info: StageInfo{Name: name, Workers: workers, Buffer: buffer},
+		fn:   fn,
+		run: func(ctx context.Context, in <-chan A, emit func(B) bool) {
+			parallel(workers, in, func(item A) bool {
+				return emit(fn(item))
+			})
+		},
+	}
+}
+
+// sequence of stages turning items of type A into items of type B
+type Chain[A, B any] struct {
+	plan    []StageInfo
+	fuse    bool
+	fusible bool // the last stage can be fused with the next one
+	// run all stages on in and pass the outputs to emit until it reports
+	// false, returns once all workers are done
+	connect func(ctx context.Context, in <-chan A, emit func(B) bool)
+}
+
+// create a new Chain starting with s, further stages are added by Then
+func NewChain[A, B any](s Stage[A, B]) *Chain[A, B] {
+	return &Chain[A, B]{plan: []StageInfo{s.info}, fuse: true, fusible: s.fusible(), connect: s.run}
+}
+
+// do not fuse the stages added by later calls of Then
+func (c *Chain[A, B]) NoFusion() *Chain[A, B] {
+	c.fuse = false
+	return c
+}
+
+// chain c followed by s, fused with the last stage of c where possible
+func Then[A, B, C any](c *Chain[A, B], s Stage[B, C]) *Chain[A, C] {
+	last := c.plan[len(c.plan)-1]
+	plan := append([]StageInfo(nil), c.plan...)
+	if c.fuse && c.fusible && s.fusible() {
+		plan[len(plan)-1].Name += "+" + s.info.Name
+		return &Chain[A, C]{
+			plan:    plan,
+			fuse:    c.fuse,
+			fusible: true,
+			connect: func(ctx context.Context, in <-chan A, emit func(C) bool) {
+				c.connect(ctx, in, func(item B) bool {
+					return emit(s.fn(item))
+				})
+			},
+		}
+	}
+	return &Chain[A, C]{
+		plan:    append(plan, s.info),
+		fuse:    c.fuse,
+		fusible: s.fusible(),
+		connect: func(ctx context.Context, in <-chan A, emit func(C) bool) {
+			hop := make(chan B, last.Buffer)
+			go func() {
+				defer close(hop)
+				c.connect(ctx, in, send(ctx, hop))
+			}()
+			s.run(ctx, hop, emit)
+		},
+	}
+}
+
+// run n goroutines calling fn for the items of in until in is closed
+// or fn reports false, wait for all of them
+func parallel[T any](n int, in <-chan T, fn func(T) bool) {
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range in {
+				if !fn(item) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// emit function sending to ch, reports false once ctx is done
+func send[T any](ctx context.Context, ch chan<- T) func(T) bool {
+	return func(item T) bool {
+		select {
+		case ch <- item:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// stages as they are run, after fusion
+func (c *Chain[A, B]) Plan() []StageInfo {
+	return c.plan
+}
+
+// send every item of in through the stages; the returned channel is
+// closed once in is closed and all items passed, or once ctx is done
+func (c *Chain[A, B]) Run(ctx context.Context, in <-chan A) <-chan B {
+	out := make(chan B, c.plan[len(c.plan)-1].Buffer)
+	go func() {
+		defer close(out)
+		c.connect(ctx, in, send(ctx, out))
+	}()
+	return out
+}
+
+// names of the planned stages, e.g. "trim+lower -> parse"
+func (c *Chain[A, B]) String() string {
+	var names []string
+	for _, s := range c.plan {
+		names = append(names, s.Name)
+	}
+	return strings.Join(names, " -> ")
+}
+
+/* Via turns a chain into a source, so a pipeline validating items of
+ * type T can be fed from a source of another type S, e.g. lines of text
+ * parsed to ints by the first stage. The chain starts with the context
+ * of the first call of Next; an error of src ends the items and is
+ * returned by Next once the items before it have passed the chain.
+ */
+
+// source of the items of src after passing c
+func Via[S, T any](src source.Source[S], c *Chain[S, T]) source.Source[T] {
+	return &via[S, T]{src: src, chain: c}
+}
+
+type via[S, T any] struct {
+	src   source.Source[S]
+	chain *Chain[S, T]
+	once  sync.Once
+	out   <-chan T
+	err   error // of src, set before out is closed
+}
+
+func (v *via[S, T]) Next(ctx context.Context) (T, bool, error) {
+	v.once.Do(func() {
+		in := make(chan S)
+		go func() {
+			defer close(in)
+			for ctx.Err() == nil {
+				item, ok, err := v.src.Next(ctx)
+				if err != nil {
+					v.err = err
+				}
+				if !ok || err != nil || !send(ctx, in)(item) {
+					return
+				}
+			}
+		}()
+		v.out = v.chain.Run(ctx, in)
+	})
+	select {
+	case item, ok := <-v.out:
+		if !ok {
+			return item, false, v.err
+		}
+		return item, true, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, false, ctx.Err()
+	}
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/* Window collects the items into batches, so the stages after it (a
+ * validator of whole batches, a sum or an average) work on slices. A
+ * batch is emitted once it holds n items or d after its first item
+ * arrived, whichever comes first, so a slow trickle of items is not held
+ * back indefinitely; the last, possibly smaller batch is emitted when the
+ * input ends. n <= 0 only uses the time limit, d <= 0 only the count.
+ * The stage keeps its batch across items and is therefore never fused.
+ */
+
+// stage grouping the items into batches of up to n items or d duration
+func Window[T any](n int, d time.Duration) Stage[T, []T] {
+	return Stage[T, []T]{
+		info: StageInfo{Name: fmt.Sprintf("window(%d, %v)", n, d), Workers: 1},
+		run: func(ctx context.Context, in <-chan T, emit func([]T) bool) {
+			var batch []T
+			var timer *time.Timer
+			var timeout <-chan time.Time
+			flush := func() bool {
+				if timer != nil {
+					timer.Stop()
+					timer, timeout = nil, nil
+				}
+				if len(batch) == 0 {
+					return true
+				}
+				full := batch
+				batch = nil
+				return emit(full)
+			}
+			for {
+				select {
+				case item, ok := <-in:
+					if !ok {
+						flush()
+						return
+					}
+					batch = append(batch, item)
+					if len(batch) == 1 && d > 0 {
+						timer = time.NewTimer(d)
+						timeout = timer.C
+					}
+					if n > 0 && len(batch) >= n && !flush() {
+						return
+					}
+				case <-timeout:
+					if !flush() {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		},
+	}
+}
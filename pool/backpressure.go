@@ -0,0 +1,150 @@
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+/* Back-pressure keeps fast producers from growing the amount of
+ * submitted but unfinished work without bound. Once the number of
+ * pending items reaches the high-water mark, SubmitBlocking parks the
+ * producer until the workers have brought it down to the low-water mark.
+ * The gap between both marks avoids waking producers for every single
+ * finished item.
+ */
+
+// park SubmitBlocking at high pending items until at most low are left
+func WithWaterMarks[T any](high, low int) Option[T] {
+	return func(o *options[T]) {
+		o.high, o.low = high, low
+	}
+}
+
+// counter of pending items with the throttling state
+type pressure struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pending   int
+	submitted int // items counted by add
+	finished  int // items counted by done
+	high, low int
+	throttled bool
+	idle      func() // called once pending drops to zero
+}
+
+// create the pressure state for the given marks (high 0 disables throttling)
+func newPressure(high, low int) *pressure {
+	pr := &pressure{high: high, low: low}
+	pr.cond = sync.NewCond(&pr.mu)
+	return pr
+}
+
+// count a submitted item
+func (pr *pressure) add() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.pending++
+	pr.submitted++
+	if pr.high > 0 && pr.pending >= pr.high {
+		pr.throttled = true
+	}
+}
+
+// count a finished item and release parked producers below the low mark
+func (pr *pressure) done() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.pending--
+	pr.finished++
+	pr.settle()
+}
+
+// take back an item counted by add that never reached a worker
+func (pr *pressure) undo() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.pending--
+	pr.submitted--
+	pr.settle()
+}
+
+// release parked producers below the low mark and call idle once
+// nothing is pending; mu has to be held
+func (pr *pressure) settle() {
+	if pr.throttled && pr.pending <= pr.low {
+		pr.throttled = false
+		pr.cond.Broadcast()
+	}
+	if pr.pending == 0 && pr.idle != nil {
+		pr.idle()
+	}
+}
+
+// call fn now if nothing is pending, otherwise once pending drops to zero
+func (pr *pressure) whenIdle(fn func()) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.pending == 0 {
+		fn()
+		return
+	}
+	pr.idle = fn
+}
+
+// block while throttled
+func (pr *pressure) wait() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	for pr.throttled {
+		pr.cond.Wait()
+	}
+}
+
+// number of submitted items that are not finished yet
+func (p *Pool[T, R]) PendingLen() int {
+	p.pressure.mu.Lock()
+	defer p.pressure.mu.Unlock()
+	return p.pressure.pending
+}
+
+// numbers of submitted, finished and pending items, taken at the same
+// instant, so finished + pending == submitted always holds
+func (p *Pool[T, R]) Counts() (submitted, finished, pending int) {
+	p.pressure.mu.Lock()
+	defer p.pressure.mu.Unlock()
+	return p.pressure.submitted, p.pressure.finished, p.pressure.pending
+}
+
+// like Submit, but first waits while the pool is above its high-water mark
+func (p *Pool[T, R]) SubmitBlocking(item T) {
+	p.pressure.wait()
+	p.Submit(item)
+}
+
+// like SubmitBlocking, but fails like SubmitContext once ctx ends
+// before the pool is below its high-water mark or a worker took the item
+func (p *Pool[T, R]) SubmitBlockingContext(ctx context.Context, item T) error {
+	if err := p.pressure.waitContext(ctx); err != nil {
+		return err
+	}
+	return p.trySubmit(ctx, item, 0)
+}
+
+// like wait, but gives up once ctx ends
+func (pr *pressure) waitContext(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		pr.mu.Lock()
+		defer pr.mu.Unlock()
+		pr.cond.Broadcast()
+	})
+	defer stop()
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	for pr.throttled {
+		if ctx.Err() != nil {
+			return ctxErr(ctx)
+		}
+		pr.cond.Wait()
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package pool
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+/* The delivery mode decides what happens to an item whose worker dies
+ * while processing it, i.e. a panic escapes all middleware or the
+ * handler calls runtime.Goexit.
+ * AtMostOnce (the default) counts an item as delivered as soon as a
+ * worker took it, so it is lost together with the worker.
+ * AtLeastOnce counts it as delivered only after it was acknowledged,
+ * either explicitly via Job.Ack or implicitly when the handler returns;
+ * an unacknowledged item of a dead worker is dispatched again and may
+ * therefore be processed more than once.
+ */
+
+// delivery semantics of a pool
+type Delivery int
+
+const (
+	AtMostOnce Delivery = iota
+	AtLeastOnce
+)
+
+// select the delivery semantics
+func WithDelivery[T any](d Delivery) Option[T] {
+	return func(o *options[T]) {
+		o.delivery = d
+	}
+}
+
+// error recorded when a worker died
+type WorkerError struct {
+	Worker int
+	Name   string // of the worker
+	ID     uint64 // item being processed
+	Cause  any    // recovered panic value, nil for runtime.Goexit
+}
+
+func (e *WorkerError) Error() string {
+	if e.Cause == nil {
+		return fmt.Sprintf("pool: %s exited while processing item %d", e.Name, e.ID)
+	}
+	return fmt.Sprintf("pool: %s died while processing item %d: %v", e.Name, e.ID, e.Cause)
+}
+
+// acknowledge the item, in AtLeastOnce mode it will not be dispatched
+// again even if the worker dies afterwards
+func (j Job[T]) Ack() {
+	if j.acked != nil {
+		j.acked.Store(true)
+	}
+}
+
+// run the handler; if the worker dies inside, record it and dispatch the
+// item again if it was not acknowledged, otherwise resolve its future with
+// the *WorkerError. Returns false after a panic.
+// With stuck detection a stuck item returns its *StuckError and the zero R.
+// acked belongs to the worker and is reused for every item.
+func (p *Pool[T, R]) call(h Handler[T, R], j job[T], worker int, acked *atomic.Bool) (value R, err error, alive bool) {
+	acked.Store(false)
+	job := Job[T]{ID: j.id, Submitted: j.submitted, Item: j.item, Worker: worker, WorkerName: p.names[worker-1], acked: acked}
+	if p.beats != nil {
+		job.beat = p.beats[worker-1]
+		job.ctx = job.beat.begin(j)
+	}
+	defer func() {
+		if job.beat != nil {
+			if stuck := job.beat.end(); stuck != nil && err == nil {
+				// the verdict came too late to count
+				var zero R
+				value, err = zero, stuck
+			}
+		}
+		if alive {
+			return
+		}
+		werr := &WorkerError{Worker: worker, Name: p.names[worker-1], ID: j.id, Cause: recover()}
+		p.setErr(werr)
+		fmt.Fprintf(p.logw, "%v, restarting worker\n", werr)
+		if p.delivery == AtLeastOnce && !acked.Load() {
+			go p.dispatch(j)
+			return
+		}
+		// the item is lost, a caller waiting for it gets the error
+		if f := p.takeFuture(j.id); f != nil {
+			var zero R
+			f.resolve(zero, werr)
+		}
+		p.pressure.done()
+	}()
+	value, err = h(job)
+	return value, err, true
+}
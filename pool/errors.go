@@ -0,0 +1,32 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/* Submitting can fail with one of these sentinel errors, so callers
+ * branch with errors.Is. A wait ended by its context fails with
+ * ErrTimeout or ErrCancelled wrapping the context's error as well, so
+ * both errors.Is(err, pool.ErrTimeout) and
+ * errors.Is(err, context.DeadlineExceeded) hold.
+ * Failures of single items are not errors of the pool: they are reported
+ * in Result.Err (e.g. a *WorkerError or a recovered panic).
+ */
+
+var (
+	ErrClosed    = errors.New("pool: closed")    // Close was called
+	ErrFull      = errors.New("pool: full")      // the input buffer is full
+	ErrTimeout   = errors.New("pool: timeout")   // the deadline of the context passed
+	ErrCancelled = errors.New("pool: cancelled") // the context was cancelled
+)
+
+// error for a wait ended by ctx
+func ctxErr(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrCancelled, err)
+}
@@ -0,0 +1,85 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* A future lets a caller wait for the result of one particular item
+ * instead of consuming the shared results channel. The result of an item
+ * submitted with SubmitFuture is handed to its future only and does not
+ * appear on Results; everything else (history, dead letters, latency,
+ * back-pressure) treats it like any other item.
+ * The worker looks its future up by the item id; as long as no future is
+ * pending, the lookup is a single atomic load, so pools without futures
+ * keep their allocation-free path.
+ */
+
+// result of a single item, available once the item is processed
+type Future[R any] struct {
+	done  chan struct{} // closed once value and err are set
+	value R
+	err   error
+}
+
+// wait for the result; fails with ErrTimeout or ErrCancelled if ctx ends first
+func (f *Future[R]) Get(ctx context.Context) (R, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero R
+		return zero, ctxErr(ctx)
+	}
+}
+
+// channel closed once the result is available
+func (f *Future[R]) Done() <-chan struct{} {
+	return f.done
+}
+
+// futures of the pending items submitted with SubmitFuture
+type futures[R any] struct {
+	mu      sync.Mutex
+	pending map[uint64]*Future[R]
+	count   atomic.Int64 // len(pending), read without the lock
+}
+
+// like Submit, but the result is only delivered to the returned future
+func (p *Pool[T, R]) SubmitFuture(item T) *Future[R] {
+	f := &Future[R]{done: make(chan struct{})}
+	j := job[T]{id: p.next.Add(1), submitted: time.Now(), item: item}
+	p.futures.mu.Lock()
+	if p.futures.pending == nil {
+		p.futures.pending = make(map[uint64]*Future[R])
+	}
+	p.futures.pending[j.id] = f
+	p.futures.count.Add(1)
+	p.futures.mu.Unlock()
+	p.submit(j)
+	return f
+}
+
+// remove and return the future of the item with id, nil if there is none
+func (p *Pool[T, R]) takeFuture(id uint64) *Future[R] {
+	if p.futures.count.Load() == 0 {
+		return nil
+	}
+	p.futures.mu.Lock()
+	defer p.futures.mu.Unlock()
+	f, ok := p.futures.pending[id]
+	if !ok {
+		return nil
+	}
+	delete(p.futures.pending, id)
+	p.futures.count.Add(-1)
+	return f
+}
+
+// hand res to f
+func (f *Future[R]) resolve(value R, err error) {
+	f.value, f.err = value, err
+	close(f.done)
+}
@@ -0,0 +1,129 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/* Stuck-worker detection: every worker beats when it takes an item,
+ * and a handler doing long work can beat again via Job.Heartbeat to show
+ * it is still making progress. A watcher checks all workers every half
+ * interval; a worker without a beat for a whole interval is reported as
+ * stuck on its item. Its result is marked failed with a *StuckError
+ * (ending up in the dead letters), and with cancel the context of the
+ * item (Job.Context) is cancelled, so context-aware handlers can give up.
+ * Without the cancel a blocked handler still keeps its worker, but the
+ * stall is no longer silent.
+ */
+
+// report items without a heartbeat for interval as stuck,
+// with cancel their Job.Context is cancelled as well
+func WithStuckDetection[T any](interval time.Duration, cancel bool) Option[T] {
+	return func(o *options[T]) {
+		o.stuck, o.cancelStuck = interval, cancel
+	}
+}
+
+// error of an item whose worker stopped making progress
+type StuckError struct {
+	Worker int
+	Name   string // of the worker
+	ID     uint64
+	After  time.Duration // time since the item was taken when it was detected
+}
+
+func (e *StuckError) Error() string {
+	return fmt.Sprintf("pool: %s stuck on item %d for %v", e.Name, e.ID, e.After.Round(time.Millisecond))
+}
+
+// heartbeat state of a single worker
+type beat[T any] struct {
+	mu     sync.Mutex
+	worker int
+	name   string
+	busy   bool
+	job    job[T]
+	start  time.Time
+	last   time.Time
+	cancel context.CancelFunc
+	stuck  *StuckError
+}
+
+// record that the worker took j, returns the context of the item
+func (b *beat[T]) begin(j job[T]) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.busy, b.job, b.start, b.last = true, j, now, now
+	b.cancel, b.stuck = cancel, nil
+	return ctx
+}
+
+// record progress on the current item
+func (b *beat[T]) beat() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last = time.Now()
+}
+
+// record that the current item is done, returns the error if it was stuck
+func (b *beat[T]) end() *StuckError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cancel()
+	b.busy = false
+	return b.stuck
+}
+
+// mark the current item stuck if its last beat is older than interval,
+// returns the new error (nil if the worker is fine or already reported)
+func (b *beat[T]) check(now time.Time, interval time.Duration, cancel bool) (*StuckError, T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.busy || b.stuck != nil || now.Sub(b.last) < interval {
+		var zero T
+		return nil, zero
+	}
+	b.stuck = &StuckError{Worker: b.worker, Name: b.name, ID: b.job.id, After: now.Sub(b.start)}
+	if cancel {
+		b.cancel()
+	}
+	return b.stuck, b.job.item
+}
+
+// check all workers every half interval until quit is closed
+func (p *Pool[T, R]) watch(interval time.Duration, cancel bool, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			return
+		case now := <-ticker.C:
+			for _, b := range p.beats {
+				if err, item := b.check(now, interval, cancel); err != nil {
+					fmt.Fprintf(p.logw, "%v, item: %v\n", err, item)
+				}
+			}
+		}
+	}
+}
+
+// context of the item, cancelled once it is done or detected as stuck
+// (see WithStuckDetection); context.Background without stuck detection
+func (j Job[T]) Context() context.Context {
+	if j.ctx == nil {
+		return context.Background()
+	}
+	return j.ctx
+}
+
+// signal that the handler is still making progress on the item
+func (j Job[T]) Heartbeat() {
+	if j.beat != nil {
+		j.beat.beat()
+	}
+}
@@ -0,0 +1,23 @@
+package pool
+
+/* A limiter caps the items processed at once over several pools, e.g.
+ * a budget of workers shared by all tenants of a manager. A worker takes
+ * a slot after it received an item and passed the pause gate, and gives
+ * it back once the handler returned. Waiting for a slot happens before
+ * the heartbeat of the item starts, so with stuck detection a worker
+ * waiting for a slot is not reported as stuck, and the latency of an
+ * item does not include the wait.
+ */
+
+// slots shared by the workers of one or more pools
+type Limiter interface {
+	Acquire() // block until a slot is free and take it
+	Release() // give a slot taken by Acquire back
+}
+
+// take a slot from l for every item processed
+func WithLimiter[T any](l Limiter) Option[T] {
+	return func(o *options[T]) {
+		o.limiter = l
+	}
+}
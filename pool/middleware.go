@@ -0,0 +1,117 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+/* Middleware wraps the work function of every worker, so cross-cutting
+ * concerns like logging, timing, retries and panic recovery are written
+ * once and combined freely instead of being baked into the worker loop.
+ * Middleware added first is the outermost one.
+ */
+
+// item handed to a handler together with its metadata
+type Job[T any] struct {
+	ID         uint64
+	Submitted  time.Time
+	Item       T
+	Worker     int
+	WorkerName string
+
+	acked *atomic.Bool
+	ctx   context.Context
+	beat  *beat[T]
+}
+
+// function processing a single item
+type Handler[T, R any] func(j Job[T]) (R, error)
+
+// function wrapping a handler
+type Middleware[T, R any] func(next Handler[T, R]) Handler[T, R]
+
+// error returned for a panic recovered by Recover
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pool: work function panicked: %v", e.Value)
+}
+
+// add middleware to all workers, items already being processed are not affected
+func (p *Pool[T, R]) Use(mw ...Middleware[T, R]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var mws []Middleware[T, R]
+	if old := p.mws.Load(); old != nil {
+		mws = append(mws, *old...)
+	}
+	mws = append(mws, mw...)
+	p.mws.Store(&mws)
+}
+
+// wrap h with mws, the first middleware ends up outermost
+func chain[T, R any](h Handler[T, R], mws []Middleware[T, R]) Handler[T, R] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// turn panics of the wrapped handler into a *PanicError
+func Recover[T, R any]() Middleware[T, R] {
+	return func(next Handler[T, R]) Handler[T, R] {
+		return func(j Job[T]) (value R, err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = &PanicError{Value: v}
+				}
+			}()
+			return next(j)
+		}
+	}
+}
+
+// call the wrapped handler up to attempts times until it returns no error
+func Retry[T, R any](attempts int) Middleware[T, R] {
+	return func(next Handler[T, R]) Handler[T, R] {
+		return func(j Job[T]) (R, error) {
+			value, err := next(j)
+			for i := 1; i < attempts && err != nil; i++ {
+				value, err = next(j)
+			}
+			return value, err
+		}
+	}
+}
+
+// report the duration of every call of the wrapped handler
+func Timing[T, R any](observe func(j Job[T], d time.Duration)) Middleware[T, R] {
+	return func(next Handler[T, R]) Handler[T, R] {
+		return func(j Job[T]) (R, error) {
+			start := time.Now()
+			value, err := next(j)
+			observe(j, time.Since(start))
+			return value, err
+		}
+	}
+}
+
+// write one line per processed item to w
+func Logging[T, R any](w io.Writer) Middleware[T, R] {
+	return func(next Handler[T, R]) Handler[T, R] {
+		return func(j Job[T]) (R, error) {
+			value, err := next(j)
+			if err != nil {
+				fmt.Fprintf(w, "%s: item %d: %v error: %v\n", j.WorkerName, j.ID, j.Item, err)
+			} else {
+				fmt.Fprintf(w, "%s: item %d: %v result: %v\n", j.WorkerName, j.ID, j.Item, value)
+			}
+			return value, err
+		}
+	}
+}
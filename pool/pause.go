@@ -0,0 +1,71 @@
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/* A paused pool keeps accepting items (up to its buffer, then Submit
+ * blocks as usual) but its workers process no new item until Resume;
+ * items in processing are finished. A waiting worker may already hold
+ * the next item, which is why the gate is checked after receiving it. Close waits
+ * for all pending items, so it only returns once the pool is resumed.
+ * The flag is checked with a single atomic load, the hot path of a
+ * running pool stays free of locks and allocations.
+ */
+
+// gate the workers wait at before processing the next item
+type gate struct {
+	paused atomic.Bool
+	mu     sync.Mutex
+	cond   *sync.Cond
+}
+
+// create an open gate
+func newGate() *gate {
+	g := &gate{}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+// block while the gate is closed
+func (g *gate) wait() {
+	if !g.paused.Load() {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.paused.Load() {
+		g.cond.Wait()
+	}
+}
+
+// open or close the gate, returns false if it already was in that state
+func (g *gate) set(paused bool) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused.CompareAndSwap(!paused, paused) {
+		return false
+	}
+	if !paused {
+		g.cond.Broadcast()
+	}
+	return true
+}
+
+// stop handing items to the workers, in-flight items still finish;
+// returns false if the pool already was paused
+func (p *Pool[T, R]) Pause() bool {
+	return p.gate.set(true)
+}
+
+// continue handing items to the workers,
+// returns false if the pool was not paused
+func (p *Pool[T, R]) Resume() bool {
+	return p.gate.set(false)
+}
+
+// reports whether the pool is paused
+func (p *Pool[T, R]) Paused() bool {
+	return p.gate.paused.Load()
+}
@@ -0,0 +1,567 @@
+package pool
+
+import (
+	"context"
+	"hash/maphash"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+	"github.com/juli-99/hka-modell_basierte_software/fanin"
+	"github.com/juli-99/hka-modell_basierte_software/hist"
+	"github.com/juli-99/hka-modell_basierte_software/partition"
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+	"github.com/juli-99/hka-modell_basierte_software/ring"
+	"github.com/juli-99/hka-modell_basierte_software/wal"
+	"github.com/juli-99/hka-modell_basierte_software/window"
+)
+
+/* Using generics instead of interfaces is necessary in this case
+ * because the work function can operate on arbitrary types,
+ * and we don't know in advance what operations it will perform.
+ * By using generics, we ensure that the item and the work function
+ * both use the same concrete type, enabling full compile-time type safety
+ * without requiring a common interface. This flexibility would not be possible
+ * with interfaces alone, since interfaces require predefined method sets.
+ */
+
+// result of processing a single item
+type Result[T, R any] struct {
+	ID         uint64 // assigned on submit, starting at 1
+	Submitted  time.Time
+	Item       T
+	Value      R
+	Err        error // set by middleware, e.g. for recovered panics
+	Worker     int
+	WorkerName string // see WithNamePrefix
+	Cycle      int    // times the item was fed back into the pool (Resubmit)
+}
+
+// item that failed permanently
+type DeadLetter[T any] struct {
+	ID        uint64
+	Submitted time.Time
+	Item      T
+	Err       error
+}
+
+/* Every item gets its id and submission time when it enters the pool.
+ * Both travel with the item through the handlers (Job), the write-ahead
+ * log, the results and the dead letters, and the id appears in all log
+ * messages of the pool, so the journey of a single item can be followed
+ * across all of them.
+ */
+
+// item together with its provenance on the way to a worker
+type job[T any] struct {
+	id        uint64
+	submitted time.Time
+	item      T
+	cycle     int
+}
+
+// optional pool settings
+type options[T any] struct {
+	log         *wal.Log[T]
+	buffer      int
+	history     int
+	hash        func(T) uint64
+	high        int
+	low         int
+	delivery    Delivery
+	logw        io.Writer
+	stuck       time.Duration
+	cancelStuck bool
+	prefix      string
+	limiter     Limiter
+}
+
+// function configuring a pool
+type Option[T any] func(*options[T])
+
+// append every submit and result to the given write-ahead log
+func WithWAL[T any](log *wal.Log[T]) Option[T] {
+	return func(o *options[T]) {
+		o.log = log
+	}
+}
+
+// buffer up to n submitted items and n results
+func WithBuffer[T any](n int) Option[T] {
+	return func(o *options[T]) {
+		o.buffer = n
+	}
+}
+
+// keep the last n results for Recent
+func WithHistory[T any](n int) Option[T] {
+	return func(o *options[T]) {
+		o.history = n
+	}
+}
+
+/* With a key function every worker gets its own input channel and
+ * an item is always sent to the worker its key hashes to. Items with the
+ * same key are therefore processed one after another by the same worker,
+ * in the order they were submitted, so work functions may keep per-key
+ * state. The worker is chosen by consistent hashing (see partition).
+ */
+
+// dispatch all items with the same key to the same worker
+func WithKey[T any, K comparable](key func(T) K) Option[T] {
+	seed := maphash.MakeSeed()
+	return func(o *options[T]) {
+		o.hash = func(item T) uint64 {
+			return maphash.Comparable(seed, key(item))
+		}
+	}
+}
+
+/* Worker ids start at 1 in every pool. When several pools run side by
+ * side, their ids repeat, so every worker also gets a name made of a
+ * prefix and its id that is unique as long as the prefixes are (e.g. the
+ * name of the pipeline). The name is carried in Job, Result and the
+ * errors of the pool, so logs, metrics and traces can tell workers of
+ * different pools apart. Names are built once in New, passing them on
+ * does not allocate.
+ */
+
+// prefix of the worker names without WithNamePrefix
+const DefaultNamePrefix = "worker-"
+
+// name the workers prefix followed by their id, e.g. "worker-ints-1"
+func WithNamePrefix[T any](prefix string) Option[T] {
+	return func(o *options[T]) {
+		o.prefix = prefix
+	}
+}
+
+// write supervision messages (dead and restarted workers) to w, default os.Stderr
+func WithLogger[T any](w io.Writer) Option[T] {
+	return func(o *options[T]) {
+		o.logw = w
+	}
+}
+
+// generic worker pool structure
+type Pool[T, R any] struct {
+	ins    []chan job[T] // one shared channel, or one per worker with a key
+	hash   func(T) uint64
+	ring   *partition.Ring // maps hashes to ins
+	out    <-chan Result[T, R]
+	once   sync.Once   // closes ins and quit
+	closed atomic.Bool // Close was called
+	quit   chan struct{}
+
+	workers  int
+	names    []string // of the workers by id-1
+	buffer   int
+	delivery Delivery
+	logw     io.Writer
+	restarts atomic.Int64
+	next     atomic.Uint64
+	log      *wal.Log[T]
+
+	mu     sync.Mutex
+	err    error
+	recent *ring.Ring[Result[T, R]]
+	done   *window.Window[int]
+	lat    *hist.Histogram
+	dead   *queue.Queue[DeadLetter[T]]
+
+	mws      atomic.Pointer[[]Middleware[T, R]]
+	work     atomic.Pointer[workFactory[T, R]]
+	swap     sync.RWMutex // held for reading while a work function runs
+	futures  futures[R]
+	pressure *pressure
+	gate     *gate
+	limiter  Limiter    // nil = no shared limit
+	beats    []*beat[T] // per worker, nil without stuck detection
+}
+
+// factory of the per-worker work functions
+type workFactory[T, R any] struct {
+	new func(workerID int) func(T) R
+}
+
+// time span the throughput is averaged over
+const RateWindow = 10 * time.Second
+
+// create a new pool and start its workers (ids starting at 1),
+// all workers share the work function fn
+func New[T, R any](workers int, fn func(T) R, opts ...Option[T]) *Pool[T, R] {
+	return NewStateful(workers, func(int) func(T) R { return fn }, opts...)
+}
+
+/* NewStateful calls the factory once per worker with its id,
+ * so every worker gets its own work function. State captured by
+ * that function (caches, counters, random generators) is only ever
+ * used by a single goroutine and needs no locking.
+ */
+
+// create a new pool whose workers each get a work function from factory
+func NewStateful[T, R any](workers int, factory func(workerID int) func(T) R, opts ...Option[T]) *Pool[T, R] {
+	o := options[T]{logw: os.Stderr, prefix: DefaultNamePrefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := &Pool[T, R]{
+		hash: o.hash,
+		log:  o.log,
+
+		workers:  workers,
+		buffer:   o.buffer,
+		delivery: o.delivery,
+		logw:     o.logw,
+		done:     window.New[int](RateWindow, 10),
+		lat:      hist.New(),
+		dead:     queue.New[DeadLetter[T]](),
+
+		pressure: newPressure(o.high, o.low),
+		gate:     newGate(),
+		limiter:  o.limiter,
+		quit:     make(chan struct{}),
+	}
+	p.work.Store(&workFactory[T, R]{new: factory})
+	for i := 1; i <= workers; i++ {
+		p.names = append(p.names, o.prefix+strconv.Itoa(i))
+	}
+	if o.history > 0 {
+		p.recent = ring.New[Result[T, R]](o.history)
+	}
+	if o.stuck > 0 {
+		for i := 1; i <= workers; i++ {
+			p.beats = append(p.beats, &beat[T]{worker: i, name: p.names[i-1]})
+		}
+		go p.watch(o.stuck, o.cancelStuck, p.quit)
+	}
+	channels := 1
+	if o.hash != nil {
+		channels = workers
+	}
+	for range channels {
+		p.ins = append(p.ins, make(chan job[T], o.buffer))
+	}
+	p.ring = partition.New(channels, partition.DefaultVirtualNodes)
+	outs := make([]<-chan Result[T, R], workers)
+	for i := 1; i <= workers; i++ {
+		out := make(chan Result[T, R], o.buffer)
+		outs[i-1] = out
+		go p.worker(i, p.ins[(i-1)%channels], out)
+	}
+	p.out = fanin.Merge(outs...)
+	return p
+}
+
+/* Workers are supervised: if a worker dies (see call), its goroutine
+ * is replaced by a new one with the same id, input and output channel
+ * and a fresh work function from the factory, so the configured number
+ * of workers is maintained.
+ *
+ * The path of an item through a worker does not allocate for value
+ * types: jobs and results are passed by value over channels, the
+ * middleware chain is only rebuilt when Use changed it, and the
+ * acknowledgement flag is allocated once per worker and reset per item.
+ * TestAllocsPerItem in pool_test.go guards this.
+ */
+
+// process items until the input is closed, then close out
+func (p *Pool[T, R]) worker(id int, in <-chan job[T], out chan<- Result[T, R]) {
+	finished := false
+	defer func() {
+		if finished {
+			close(out)
+			return
+		}
+		p.restarts.Add(1)
+		go p.worker(id, in, out)
+	}()
+	made := p.work.Load()
+	fn := made.new(id)
+	base := func(j Job[T]) (R, error) {
+		return fn(j.Item), nil
+	}
+	handler := Handler[T, R](base)
+	var built *[]Middleware[T, R]
+	acked := new(atomic.Bool)
+	for j := range in {
+		p.gate.wait()
+		if mws := p.mws.Load(); mws != built {
+			handler, built = chain(base, *mws), mws
+		}
+		if p.limiter != nil {
+			p.limiter.Acquire()
+		}
+		start := time.Now()
+		value, err, alive := func() (R, error, bool) {
+			// deferred, so a handler calling runtime.Goexit does not leak the lock or the slot
+			if p.limiter != nil {
+				defer p.limiter.Release()
+			}
+			p.swap.RLock()
+			defer p.swap.RUnlock()
+			if f := p.work.Load(); f != made {
+				fn, made = f.new(id), f
+			}
+			return p.call(handler, j, id, acked)
+		}()
+		if !alive {
+			return
+		}
+		p.lat.Record(time.Since(start))
+		if p.log != nil {
+			p.setErr(p.log.Result(j.id))
+		}
+		res := Result[T, R]{ID: j.id, Submitted: j.submitted, Item: j.item, Value: value, Err: err, Worker: id, WorkerName: p.names[id-1], Cycle: j.cycle}
+		p.done.Add(1)
+		p.mu.Lock()
+		if p.recent != nil {
+			p.recent.Push(res)
+		}
+		if err != nil {
+			p.dead.Add(DeadLetter[T]{ID: j.id, Submitted: j.submitted, Item: j.item, Err: err})
+		}
+		p.mu.Unlock()
+		p.pressure.done()
+		if f := p.takeFuture(j.id); f != nil {
+			f.resolve(value, err)
+			continue
+		}
+		out <- res
+	}
+	finished = true
+}
+
+/* SetWorkFunc is a barrier: it waits until every running work function
+ * has returned, and workers pick up the new function before they process
+ * their next item. An item, including all its retries, is therefore
+ * processed either by the old or by the new function, never by both,
+ * and no item is processed with the old function once SetWorkFunc returned.
+ */
+
+// replace the work function of all workers
+func (p *Pool[T, R]) SetWorkFunc(fn func(T) R) {
+	p.SetWorkFactory(func(int) func(T) R { return fn })
+}
+
+// replace the work functions of all workers by new ones from factory (see NewStateful)
+func (p *Pool[T, R]) SetWorkFactory(factory func(workerID int) func(T) R) {
+	p.swap.Lock()
+	defer p.swap.Unlock()
+	p.work.Store(&workFactory[T, R]{new: factory})
+}
+
+// hand an item to the next free worker (or the worker of its key),
+// blocks until the worker accepts it
+func (p *Pool[T, R]) Submit(item T) {
+	p.submit(job[T]{id: p.next.Add(1), submitted: time.Now(), item: item})
+}
+
+// like SubmitBlockingContext for an item fed back into the pool the
+// cycle-th time (see pipeline.Feedback), the cycle is reported in its Result
+func (p *Pool[T, R]) Resubmit(ctx context.Context, item T, cycle int) error {
+	if err := p.pressure.waitContext(ctx); err != nil {
+		return err
+	}
+	return p.trySubmit(ctx, item, cycle)
+}
+
+// log, count and dispatch a new job
+func (p *Pool[T, R]) submit(j job[T]) {
+	if p.log != nil {
+		p.setErr(p.log.Submit(j.id, j.submitted, j.item))
+	}
+	p.pressure.add()
+	p.dispatch(j)
+}
+
+// send a job to the input channel responsible for it
+func (p *Pool[T, R]) dispatch(j job[T]) {
+	p.input(j) <- j
+}
+
+// input channel responsible for a job
+func (p *Pool[T, R]) input(j job[T]) chan<- job[T] {
+	if p.hash == nil {
+		return p.ins[0]
+	}
+	return p.ins[p.ring.Get(p.hash(j.item))]
+}
+
+/* TrySubmit and SubmitContext report with an error why an item was not
+ * taken instead of blocking forever. A rejected item is taken back from
+ * the pending count and, with a write-ahead log, recorded as finished,
+ * so Recover does not resubmit an item the caller already knows failed.
+ * Like Submit they must not be called concurrently with Close.
+ */
+
+// hand an item to a worker without blocking; fails with ErrFull if
+// the input buffer is full and with ErrClosed after Close
+func (p *Pool[T, R]) TrySubmit(item T) error {
+	return p.trySubmit(nil, item, 0)
+}
+
+// like Submit, but fails with ErrTimeout or ErrCancelled once ctx
+// ends before a worker accepted the item, and with ErrClosed after Close
+func (p *Pool[T, R]) SubmitContext(ctx context.Context, item T) error {
+	return p.trySubmit(ctx, item, 0)
+}
+
+// submit an item fed back cycle times unless the pool is closed;
+// without ctx only if the item can be sent at once
+func (p *Pool[T, R]) trySubmit(ctx context.Context, item T, cycle int) error {
+	if p.closed.Load() {
+		return ErrClosed
+	}
+	j := job[T]{id: p.next.Add(1), submitted: time.Now(), item: item, cycle: cycle}
+	if p.log != nil {
+		p.setErr(p.log.Submit(j.id, j.submitted, j.item))
+	}
+	p.pressure.add()
+	in := p.input(j)
+	select {
+	case in <- j:
+		return nil
+	default:
+	}
+	err := ErrFull
+	if ctx != nil {
+		select {
+		case in <- j:
+			return nil
+		case <-ctx.Done():
+			err = ctxErr(ctx)
+		}
+	}
+	if p.log != nil {
+		p.setErr(p.log.Result(j.id))
+	}
+	p.pressure.undo()
+	return err
+}
+
+// resubmit all items of the log at path that were submitted but never
+// finished; has to be called before the first Submit.
+// Items are decoded with the codec of the pool's log (JSON without a log).
+// The items are counted as pending right away but dispatched in the
+// background, as there may be more than the workers and buffers take
+// before anyone reads Results.
+// Returns the number of recovered items.
+func (p *Pool[T, R]) Recover(path string) (int, error) {
+	var c codec.Codec[T] = codec.JSON[T]()
+	if p.log != nil {
+		c = p.log.Codec()
+	}
+	pending, last, err := wal.Recover(path, c)
+	if err != nil {
+		return 0, err
+	}
+	p.next.Store(last)
+	for range pending {
+		p.pressure.add()
+	}
+	go func() {
+		for _, e := range pending {
+			p.dispatch(job[T]{id: e.ID, submitted: e.Submitted, item: e.Item})
+		}
+	}()
+	return len(pending), nil
+}
+
+// channel delivering the results, closed after Close once all items are done
+func (p *Pool[T, R]) Results() <-chan Result[T, R] {
+	return p.out
+}
+
+// signal that no more items will be submitted; the workers stop once
+// every pending item is done (including items dispatched again)
+func (p *Pool[T, R]) Close() {
+	p.closed.Store(true)
+	p.pressure.whenIdle(func() {
+		p.once.Do(func() {
+			for _, in := range p.ins {
+				close(in)
+			}
+			close(p.quit)
+		})
+	})
+}
+
+// last processed results from oldest to newest, empty without WithHistory
+func (p *Pool[T, R]) Recent() []Result[T, R] {
+	if p.recent == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.recent.Snapshot()
+}
+
+/* An item whose handler still returns an error after all middleware
+ * (e.g. Retry gave up or Recover caught a panic) is added to the
+ * dead-letter queue in addition to being reported as a Result,
+ * so failures can be inspected or resubmitted later.
+ */
+
+// queue of permanently failed items, only safe to use after Results is closed
+func (p *Pool[T, R]) DeadLetters() *queue.Queue[DeadLetter[T]] {
+	return p.dead
+}
+
+// histogram of the time spent processing each item
+func (p *Pool[T, R]) Latency() *hist.Histogram {
+	return p.lat
+}
+
+// processed items per second over the last RateWindow
+func (p *Pool[T, R]) Rate() float64 {
+	return p.done.Rate()
+}
+
+// number of workers
+func (p *Pool[T, R]) Workers() int {
+	return p.workers
+}
+
+// name of the worker with the given id (see WithNamePrefix)
+func (p *Pool[T, R]) WorkerName(id int) string {
+	return p.names[id-1]
+}
+
+// size of the input buffer and of every worker's output buffer
+func (p *Pool[T, R]) Buffer() int {
+	return p.buffer
+}
+
+// reports whether every worker has its own input channel (WithKey)
+func (p *Pool[T, R]) Keyed() bool {
+	return p.hash != nil
+}
+
+// number of workers replaced after they died
+func (p *Pool[T, R]) Restarts() int64 {
+	return p.restarts.Load()
+}
+
+// first error of the pool (writing the write-ahead log, a dead worker), if any
+func (p *Pool[T, R]) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// remember the first error
+func (p *Pool[T, R]) setErr(err error) {
+	if err == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
@@ -0,0 +1,137 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+	"github.com/juli-99/hka-modell_basierte_software/wal"
+)
+
+/* testing.AllocsPerRun counts the allocations of a whole
+ * submit/result round trip, including the worker goroutine,
+ * as long as nothing else runs at the same time.
+ */
+
+// the hot path of a pool with value types does not allocate per item
+func TestAllocsPerItem(t *testing.T) {
+	p := New(1, func(n int) int { return n + 1 })
+	defer p.Close()
+	p.Use(Recover[int, int]())
+	i := 0
+	if a := testing.AllocsPerRun(1000, func() {
+		p.Submit(i)
+		<-p.Results()
+		i++
+	}); a != 0 {
+		t.Fatalf("%.1f allocations per item, want 0", a)
+	}
+}
+
+// a stuck item reports its StuckError without the late verdict
+func TestStuckResultIsZero(t *testing.T) {
+	p := New(1, func(n int) bool {
+		time.Sleep(100 * time.Millisecond)
+		return true
+	}, WithStuckDetection[int](20*time.Millisecond, false))
+	p.Submit(1)
+	res := <-p.Results()
+	p.Close()
+	var stuck *StuckError
+	if !errors.As(res.Err, &stuck) {
+		t.Fatalf("Err = %v, want a *StuckError", res.Err)
+	}
+	if res.Value {
+		t.Fatal("a stuck item kept the verdict of its handler")
+	}
+}
+
+// recovering more items than the workers and buffers hold does not block
+// before Results is read
+func TestRecoverBeyondCapacity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.jsonl")
+	log, err := wal.Open(path, codec.JSON[int]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 50
+	for i := range n {
+		if err := log.Submit(uint64(i+1), time.Now(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log.Close()
+
+	p := New(1, func(n int) int { return n }, WithBuffer[int](1))
+	recovered := make(chan int, 1)
+	go func() {
+		count, err := p.Recover(path)
+		if err != nil {
+			t.Error(err)
+		}
+		recovered <- count
+	}()
+	select {
+	case count := <-recovered:
+		if count != n {
+			t.Fatalf("recovered %d items, want %d", count, n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Recover blocks before Results is read")
+	}
+	p.Close()
+	results := 0
+	for range p.Results() {
+		results++
+	}
+	if results != n {
+		t.Fatalf("%d results, want %d", results, n)
+	}
+}
+
+// a throttled SubmitBlockingContext gives up once its context ends
+// and takes the item back from the pending count
+func TestSubmitBlockingContextThrottled(t *testing.T) {
+	release := make(chan struct{})
+	p := New(1, func(n int) int {
+		<-release
+		return n
+	}, WithWaterMarks[int](1, 0))
+	p.Submit(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.SubmitBlockingContext(ctx, 2); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("SubmitBlockingContext = %v, want ErrTimeout", err)
+	}
+	if pending := p.PendingLen(); pending != 1 {
+		t.Fatalf("PendingLen = %d, want 1", pending)
+	}
+	close(release)
+	p.Close()
+	for range p.Results() {
+	}
+}
+
+// limiter with a single slot
+type oneSlot chan struct{}
+
+func (s oneSlot) Acquire() { s <- struct{}{} }
+func (s oneSlot) Release() { <-s }
+
+// waiting for a slot of the limiter does not count as being stuck
+func TestLimiterWaitIsNotStuck(t *testing.T) {
+	slot := make(oneSlot, 1)
+	slot.Acquire() // held by another pool
+	p := New(1, func(n int) int { return n }, WithLimiter[int](slot), WithStuckDetection[int](20*time.Millisecond, false))
+	p.Submit(1)
+	time.Sleep(100 * time.Millisecond)
+	slot.Release()
+	res := <-p.Results()
+	p.Close()
+	if res.Err != nil {
+		t.Fatalf("Err = %v, want nil", res.Err)
+	}
+}
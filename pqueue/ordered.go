@@ -0,0 +1,20 @@
+package pqueue
+
+import "cmp"
+
+/* Convenience constructors for element types with a natural order,
+ * so ints or strings need no comparison function. Any other T (or a
+ * different order) uses New with its own less function.
+ */
+
+// create a new PQueue, Pop returns the smallest item
+func NewMin[T cmp.Ordered]() *PQueue[T] {
+	return New(cmp.Less[T])
+}
+
+// create a new PQueue, Pop returns the largest item
+func NewMax[T cmp.Ordered]() *PQueue[T] {
+	return New(func(a, b T) bool {
+		return cmp.Less(b, a)
+	})
+}
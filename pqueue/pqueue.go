@@ -0,0 +1,88 @@
+package pqueue
+
+/* The priority queue is a binary min-heap ordered by a less function.
+ * Passing the comparison as a function (instead of requiring an ordered
+ * element type) lets the same generic code order ints, strings or
+ * structs by any field, e.g. scheduled items by their due time.
+ */
+
+// generic priority queue structure
+type PQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// create a new PQueue, Pop returns the smallest item according to less
+func New[T any](less func(a, b T) bool) *PQueue[T] {
+	return &PQueue[T]{less: less}
+}
+
+// add item to the queue
+func (q *PQueue[T]) Push(item T) {
+	q.items = append(q.items, item)
+	q.up(len(q.items) - 1)
+}
+
+// remove and return the smallest item
+func (q *PQueue[T]) Pop() (T, bool) {
+	if len(q.items) == 0 {
+		var default_val T
+		return default_val, false // return default value and false if queue is empty
+	}
+	item := q.items[0]
+	last := len(q.items) - 1
+	q.items[0] = q.items[last]
+	var zero T
+	q.items[last] = zero
+	q.items = q.items[:last]
+	q.down(0)
+	return item, true
+}
+
+// return the smallest item
+func (q *PQueue[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false // return default value and false if queue is empty
+	}
+	return q.items[0], true
+}
+
+// number of items in the queue
+func (q *PQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// checks if the queue is empty
+func (q *PQueue[T]) IsEmpty() bool {
+	return len(q.items) == 0
+}
+
+// move the item at i up until its parent is not greater
+func (q *PQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i], q.items[parent]) {
+			return
+		}
+		q.items[i], q.items[parent] = q.items[parent], q.items[i]
+		i = parent
+	}
+}
+
+// move the item at i down until no child is smaller
+func (q *PQueue[T]) down(i int) {
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(q.items) && q.less(q.items[child], q.items[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		q.items[i], q.items[smallest] = q.items[smallest], q.items[i]
+		i = smallest
+	}
+}
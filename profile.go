@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // registers the /debug/pprof handlers
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+/* Profiling without code edits: -pprof serves the live profiles of
+ * net/http/pprof while the pipelines run (go tool pprof http://addr/debug/pprof/profile),
+ * -cpuprofile and -memprofile write profiles of the whole run to files.
+ */
+
+// start the requested profiling, the returned function ends it
+func startProfiling(addr, cpu_path, mem_path string) (func() error, error) {
+	var stops []func() error
+	stop := func() error {
+		var errs []error
+		for i := len(stops) - 1; i >= 0; i-- {
+			errs = append(errs, stops[i]())
+		}
+		return errors.Join(errs...)
+	}
+
+	if addr != "" {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "pprof: serving on http://%s/debug/pprof/\n", l.Addr())
+		srv := &http.Server{Handler: http.DefaultServeMux}
+		go srv.Serve(l)
+		stops = append(stops, srv.Close)
+	}
+
+	if cpu_path != "" {
+		f, err := os.Create(cpu_path)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if mem_path != "" {
+		stops = append(stops, func() error {
+			f, err := os.Create(mem_path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			runtime.GC() // up-to-date statistics
+			return pprof.WriteHeapProfile(f)
+		})
+	}
+	return stop, nil
+}
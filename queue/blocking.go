@@ -0,0 +1,165 @@
+package queue
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+/* A blocking queue lets consumers wait for items with a condition
+ * variable instead of a channel. Unlike a channel it is unbounded and
+ * can wrap any Queuer, so the underlying implementation stays exchangeable.
+ * Waiting always happens in a loop that re-checks the queue after every
+ * wake-up: a wake-up only means the state may have changed (another
+ * consumer may have taken the item first, or the wake-up is spurious),
+ * never that an item is guaranteed to be there.
+ *
+ * Close ends the queue for producers and, once it is drained, for consumers:
+ * it broadcasts to all waiting consumers, which then return the remaining
+ * items and afterwards ErrClosed, so a consumer looping on WaitNext stops
+ * cleanly. Because Add can fail after Close, Blocking is not a Queuer.
+ */
+
+// generic blocking queue structure, safe for concurrent use
+type Blocking[T any] struct {
+	mu     sync.Mutex
+	ready  *sync.Cond // signalled after every Add
+	q      Queuer[T]
+	closed bool
+}
+
+// create a new blocking queue around q, a slice queue if q is nil
+func NewBlocking[T any](q Queuer[T]) *Blocking[T] {
+	if q == nil {
+		q = New[T]()
+	}
+	b := &Blocking[T]{q: q}
+	b.ready = sync.NewCond(&b.mu)
+	return b
+}
+
+// add item to the end of the queue and wake up one waiting consumer,
+// fails with ErrClosed after Close
+func (b *Blocking[T]) Add(item T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	b.q.Add(item)
+	b.ready.Signal()
+	return nil
+}
+
+// remove and return from the front of the queue without waiting
+func (b *Blocking[T]) Next() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.Next()
+}
+
+// remove and return from the front of the queue, blocks until an item
+// is available; returns ErrClosed once the queue is closed and empty
+func (b *Blocking[T]) WaitNext() (T, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.q.IsEmpty() && !b.closed {
+		b.ready.Wait()
+	}
+	if b.q.IsEmpty() {
+		var zero T
+		return zero, false, ErrClosed // return default value and false if queue is closed
+	}
+	item, _ := b.q.Next()
+	return item, true, nil
+}
+
+// remove and return from the front of the queue without waiting; fails with
+// ErrEmpty if there is no item, or ErrClosed if the queue is closed as well
+func (b *Blocking[T]) TryNext() (T, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if item, ok := b.q.Next(); ok {
+		return item, nil
+	}
+	var zero T
+	if b.closed {
+		return zero, ErrClosed
+	}
+	return zero, ErrEmpty
+}
+
+// like WaitNext, but gives up with ErrTimeout or ErrCancelled once ctx ends
+func (b *Blocking[T]) WaitNextContext(ctx context.Context) (T, error) {
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.ready.Broadcast()
+	})
+	defer stop()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.q.IsEmpty() && !b.closed && ctx.Err() == nil {
+		b.ready.Wait()
+	}
+	if item, ok := b.q.Next(); ok {
+		return item, nil
+	}
+	var zero T
+	if b.closed {
+		return zero, ErrClosed
+	}
+	return zero, ctxErr(ctx)
+}
+
+// reject further items and release all waiting consumers
+func (b *Blocking[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.ready.Broadcast()
+}
+
+// return from the front of the queue
+func (b *Blocking[T]) Peek() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.Peek()
+}
+
+// checks if the queue is empty
+func (b *Blocking[T]) IsEmpty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.IsEmpty()
+}
+
+/* Snapshot copies the queue while holding the lock, so observers like a
+ * stats endpoint see a consistent state even while producers and
+ * consumers keep working. Queuer has no way to look at all items, so
+ * they are taken out and added back in the same order, unless the queue
+ * can take a snapshot itself (COW does so in O(1)).
+ */
+
+// all items from front to back, must not be modified
+func (b *Blocking[T]) Snapshot() []T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s, ok := b.q.(interface{ Snapshot() []T }); ok {
+		return s.Snapshot()
+	}
+	var items []T
+	for item, ok := b.q.Next(); ok; item, ok = b.q.Next() {
+		items = append(items, item)
+	}
+	for _, item := range items {
+		b.q.Add(item)
+	}
+	return items
+}
+
+// iterate over a snapshot of the queue from front to back
+func (b *Blocking[T]) All() iter.Seq[T] {
+	return slices.Values(b.Snapshot())
+}
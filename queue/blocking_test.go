@@ -0,0 +1,225 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/internal/lincheck"
+)
+
+// time given to goroutines to block in a wait
+const settle = 20 * time.Millisecond
+
+// a waiter woken while the queue is empty has to wait again
+func TestBlockingWakeUpWhileEmpty(t *testing.T) {
+	b := NewBlocking[int](nil)
+	got := make(chan int)
+	go func() {
+		item, _, _ := b.WaitNext()
+		got <- item
+	}()
+	time.Sleep(settle)
+
+	// spurious wake-up
+	b.mu.Lock()
+	b.ready.Broadcast()
+	b.mu.Unlock()
+	select {
+	case item := <-got:
+		t.Fatalf("WaitNext returned %d from an empty queue", item)
+	case <-time.After(settle):
+	}
+
+	b.Add(7)
+	if item := <-got; item != 7 {
+		t.Fatalf("WaitNext = %d, want 7", item)
+	}
+}
+
+// an item taken by another consumer first sends the woken waiter back to waiting
+func TestBlockingItemTakenFirst(t *testing.T) {
+	b := NewBlocking[int](nil)
+	got := make(chan int)
+	go func() {
+		item, _, _ := b.WaitNext()
+		got <- item
+	}()
+	time.Sleep(settle)
+
+	// add and take the item before the waiter can run
+	b.mu.Lock()
+	b.q.Add(1)
+	b.ready.Signal()
+	b.q.Next()
+	b.mu.Unlock()
+	select {
+	case item := <-got:
+		t.Fatalf("WaitNext returned %d, the item was already taken", item)
+	case <-time.After(settle):
+	}
+
+	b.Add(2)
+	if item := <-got; item != 2 {
+		t.Fatalf("WaitNext = %d, want 2", item)
+	}
+}
+
+// all concurrent waiters are released with ErrClosed by Close
+func TestBlockingCloseReleasesWaiters(t *testing.T) {
+	b := NewBlocking[int](nil)
+	const waiters = 8
+	errs := make(chan error, waiters)
+	var wg sync.WaitGroup
+	for range waiters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := b.WaitNext()
+			if ok {
+				t.Error("WaitNext took an item from an empty queue")
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(settle)
+	b.Close()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("WaitNext = %v, want ErrClosed", err)
+		}
+	}
+}
+
+// a closed queue rejects new items but hands out the remaining ones first
+func TestBlockingCloseDrains(t *testing.T) {
+	b := NewBlocking[int](nil)
+	b.Add(1)
+	b.Add(2)
+	b.Close()
+	if err := b.Add(3); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Add after Close = %v, want ErrClosed", err)
+	}
+	for _, want := range []int{1, 2} {
+		item, ok, err := b.WaitNext()
+		if !ok || err != nil || item != want {
+			t.Fatalf("WaitNext = %d, %v, %v, want %d", item, ok, err, want)
+		}
+	}
+	if _, _, err := b.WaitNext(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("WaitNext on a drained queue = %v, want ErrClosed", err)
+	}
+	if _, err := b.TryNext(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("TryNext on a drained queue = %v, want ErrClosed", err)
+	}
+	if _, err := b.WaitNextContext(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("WaitNextContext on a drained queue = %v, want ErrClosed", err)
+	}
+}
+
+// an open, empty queue fails with ErrEmpty without waiting and with ErrTimeout once the context ends
+func TestBlockingEmpty(t *testing.T) {
+	b := NewBlocking[int](nil)
+	if _, err := b.TryNext(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("TryNext = %v, want ErrEmpty", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), settle)
+	defer cancel()
+	_, err := b.WaitNextContext(ctx)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitNextContext = %v, want ErrTimeout", err)
+	}
+}
+
+// a snapshot lists the items from front to back and leaves the queue unchanged
+func TestBlockingSnapshot(t *testing.T) {
+	for name, q := range map[string]Queuer[int]{"slice": New[int](), "cow": NewCOW[int]()} {
+		b := NewBlocking(q)
+		for i := range 5 {
+			b.Add(i)
+		}
+		b.Next()
+		if got := b.Snapshot(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+			t.Fatalf("%s: Snapshot = %v, want [1 2 3 4]", name, got)
+		}
+		if got := slices.Collect(b.All()); !slices.Equal(got, []int{1, 2, 3, 4}) {
+			t.Fatalf("%s: All = %v, want [1 2 3 4]", name, got)
+		}
+		if item, _ := b.Next(); item != 1 {
+			t.Fatalf("%s: Next after Snapshot = %d, want 1", name, item)
+		}
+	}
+}
+
+// snapshots taken while a producer and a consumer keep working are
+// always a consecutive run of the produced sequence
+func TestBlockingSnapshotConcurrent(t *testing.T) {
+	b := NewBlocking[int](nil)
+	const n = 10000
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range n {
+			b.Add(i)
+		}
+		b.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		for want := 0; ; want++ {
+			item, ok, _ := b.WaitNext()
+			if !ok {
+				return
+			}
+			if item != want {
+				t.Errorf("WaitNext = %d, want %d", item, want)
+				return
+			}
+		}
+	}()
+	for range 200 {
+		items := b.Snapshot()
+		for i := 1; i < len(items); i++ {
+			if items[i] != items[i-1]+1 {
+				t.Fatalf("inconsistent snapshot %v", items)
+			}
+		}
+	}
+	wg.Wait()
+}
+
+// histories of concurrent add and next calls are linearizable
+func TestBlockingLinearizable(t *testing.T) {
+	const goroutines, ops = 4, 8
+	for round := range 50 {
+		b := NewBlocking[int](nil)
+		var h lincheck.History[int]
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range ops {
+					if (g+i)%2 == 0 {
+						item := g*ops + i
+						h.Do("add", item, func() (int, bool) {
+							return 0, b.Add(item) == nil
+						})
+						continue
+					}
+					h.Do("next", 0, b.Next)
+				}
+			}()
+		}
+		wg.Wait()
+		if !lincheck.Check(lincheck.QueueModel[int](), h.Ops()) {
+			t.Fatalf("round %d: history is not linearizable: %+v", round, h.Ops())
+		}
+	}
+}
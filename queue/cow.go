@@ -0,0 +1,75 @@
+package queue
+
+/* The copy-on-write queue hands out snapshots in O(1): Snapshot returns
+ * the live window of the backing slice and marks it as shared instead of
+ * copying it, so a metrics endpoint or a TUI can sample a huge queue
+ * without stalling producers and consumers.
+ *
+ * Add never writes into a shared part: the snapshot is cut with its
+ * capacity equal to its length, and the queue only ever writes behind the
+ * last item, so appending leaves the snapshot untouched (and append
+ * reallocates by itself once the backing slice is full). Next has to clear
+ * the slot it takes out, so the item can be collected; that is the only
+ * write into existing slots, so the first Next after a Snapshot copies the
+ * remaining items once. The cost of a snapshot is thereby moved to the
+ * mutation that needs it and paid at most once per snapshot.
+ */
+
+// generic copy-on-write queue structure
+type COW[T any] struct {
+	items  []T
+	shared bool // items may be referenced by a snapshot
+}
+
+// create a new COW queue
+func NewCOW[T any]() *COW[T] {
+	return &COW[T]{}
+}
+
+// add item to the end of queue
+func (q *COW[T]) Add(item T) {
+	q.items = append(q.items, item)
+}
+
+// remove and return from the front of the queue
+func (q *COW[T]) Next() (T, bool) {
+	var zero T
+	if len(q.items) == 0 {
+		return zero, false // return default value and false if queue is empty
+	}
+	if q.shared {
+		q.items = append([]T(nil), q.items...)
+		q.shared = false
+	}
+	item := q.items[0]
+	q.items[0] = zero
+	q.items = q.items[1:]
+	return item, true
+}
+
+// return from the front of the queue
+func (q *COW[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false // return default value and false if queue is empty
+	}
+	return q.items[0], true
+}
+
+// checks if the queue is empty
+func (q *COW[T]) IsEmpty() bool {
+	return len(q.items) == 0
+}
+
+// number of items in the queue
+func (q *COW[T]) Len() int {
+	return len(q.items)
+}
+
+// all items from front to back in O(1); the snapshot shares memory with
+// the queue and must not be modified, later changes of the queue do not
+// show up in it
+func (q *COW[T]) Snapshot() []T {
+	q.shared = true
+	return q.items[:len(q.items):len(q.items)]
+}
@@ -0,0 +1,30 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/* Operations that can fail return one of these sentinel errors, so
+ * callers branch with errors.Is instead of interpreting a bool.
+ * A wait ended by its context fails with ErrTimeout or ErrCancelled
+ * wrapping the context's error as well, so both
+ * errors.Is(err, queue.ErrTimeout) and errors.Is(err, context.DeadlineExceeded) hold.
+ */
+
+var (
+	ErrEmpty     = errors.New("queue: empty")     // no item to take
+	ErrClosed    = errors.New("queue: closed")    // the queue was closed
+	ErrTimeout   = errors.New("queue: timeout")   // the deadline of the context passed
+	ErrCancelled = errors.New("queue: cancelled") // the context was cancelled
+)
+
+// error for a wait ended by ctx
+func ctxErr(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrCancelled, err)
+}
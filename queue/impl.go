@@ -0,0 +1,197 @@
+package queue
+
+import (
+	"fmt"
+
+	"github.com/juli-99/hka-modell_basierte_software/arena"
+)
+
+/* Several queue implementations with the same behavior but different
+ * cost profiles, selectable at construction time for comparisons:
+ *   ImplSlice    appends to a slice and reslices on Next (Queue)
+ *   ImplRing     circular buffer that doubles when full (Circular)
+ *   ImplLinked   singly linked list with a tail pointer (Linked)
+ *   ImplTwoStack two stacks, refilling the output stack when empty
+ *   ImplCOW      slice with O(1) copy-on-write snapshots (COW)
+ */
+
+// queue implementation
+type Impl int
+
+const (
+	ImplSlice Impl = iota
+	ImplRing
+	ImplLinked
+	ImplTwoStack
+	ImplCOW
+)
+
+func (i Impl) String() string {
+	switch i {
+	case ImplSlice:
+		return "slice"
+	case ImplRing:
+		return "ring"
+	case ImplLinked:
+		return "linked"
+	case ImplTwoStack:
+		return "twostack"
+	case ImplCOW:
+		return "cow"
+	}
+	return fmt.Sprintf("Impl(%d)", int(i))
+}
+
+// implementation with the given name ("slice", "ring", "linked", "twostack", "cow")
+func ParseImpl(name string) (Impl, error) {
+	for impl := ImplSlice; impl <= ImplCOW; impl++ {
+		if impl.String() == name {
+			return impl, nil
+		}
+	}
+	return 0, fmt.Errorf("queue: unknown implementation %q", name)
+}
+
+// create a new queue of the given implementation
+func NewWith[T any](impl Impl) Queuer[T] {
+	switch impl {
+	case ImplRing:
+		return NewCircular[T]()
+	case ImplLinked:
+		return NewLinked[T]()
+	case ImplTwoStack:
+		return NewTwoStack[T]()
+	case ImplCOW:
+		return NewCOW[T]()
+	}
+	return New[T]()
+}
+
+// generic circular buffer queue structure
+type Circular[T any] struct {
+	items []T
+	head  int
+	n     int
+}
+
+// create a new Circular queue
+func NewCircular[T any]() *Circular[T] {
+	return &Circular[T]{}
+}
+
+// add item to the end of queue
+func (q *Circular[T]) Add(item T) {
+	if q.n == len(q.items) {
+		grown := make([]T, max(2*len(q.items), 8))
+		for i := range q.n {
+			grown[i] = q.items[(q.head+i)%len(q.items)]
+		}
+		q.items, q.head = grown, 0
+	}
+	q.items[(q.head+q.n)%len(q.items)] = item
+	q.n++
+}
+
+// remove and return from the front of the queue
+func (q *Circular[T]) Next() (T, bool) {
+	var zero T
+	if q.n == 0 {
+		return zero, false // return default value and false if queue is empty
+	}
+	item := q.items[q.head]
+	q.items[q.head] = zero
+	q.head = (q.head + 1) % len(q.items)
+	q.n--
+	return item, true
+}
+
+// return from the front of the queue
+func (q *Circular[T]) Peek() (T, bool) {
+	if q.n == 0 {
+		var zero T
+		return zero, false // return default value and false if queue is empty
+	}
+	return q.items[q.head], true
+}
+
+// checks if the queue is empty
+func (q *Circular[T]) IsEmpty() bool {
+	return q.n == 0
+}
+
+// single element of a linked queue
+type node[T any] struct {
+	item T
+	next *node[T]
+}
+
+// generic linked-list queue structure
+type Linked[T any] struct {
+	head, tail *node[T]
+	arena      *arena.Arena[node[T]] // nil allocates every node on its own
+}
+
+// create a new Linked queue
+func NewLinked[T any]() *Linked[T] {
+	return &Linked[T]{}
+}
+
+// create a new Linked queue allocating its nodes in blocks of size (see arena)
+func NewLinkedArena[T any](size int) *Linked[T] {
+	return &Linked[T]{arena: arena.New[node[T]](size)}
+}
+
+// add item to the end of queue
+func (q *Linked[T]) Add(item T) {
+	var n *node[T]
+	if q.arena == nil {
+		n = &node[T]{item: item}
+	} else {
+		n = q.arena.Alloc()
+		n.item = item
+	}
+	if q.tail == nil {
+		q.head = n
+	} else {
+		q.tail.next = n
+	}
+	q.tail = n
+}
+
+// remove and return from the front of the queue
+func (q *Linked[T]) Next() (T, bool) {
+	if q.head == nil {
+		var zero T
+		return zero, false // return default value and false if queue is empty
+	}
+	old := q.head
+	q.head = old.next
+	if q.head == nil {
+		q.tail = nil
+	}
+	item := old.item
+	*old = node[T]{} // an arena block keeps the node alive, it must not keep the item and the rest of the list
+	return item, true
+}
+
+// return from the front of the queue
+func (q *Linked[T]) Peek() (T, bool) {
+	if q.head == nil {
+		var zero T
+		return zero, false // return default value and false if queue is empty
+	}
+	return q.head.item, true
+}
+
+// checks if the queue is empty
+func (q *Linked[T]) IsEmpty() bool {
+	return q.head == nil
+}
+
+// remove all elements at once, dropping the arena blocks with them
+func (q *Linked[T]) Free() {
+	q.head, q.tail = nil, nil
+	if q.arena != nil {
+		q.arena.Free()
+	}
+}
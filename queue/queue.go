@@ -1,5 +1,13 @@
 package queue
 
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juli-99/hka-modell_basierte_software/growth"
+)
+
 /* Using generics for the queue makes sense
  * if we want it to be type-safe and consistent.
  * If we needed a queue that could store different types of values,
@@ -9,9 +17,28 @@ package queue
  * avoiding the need for type assertions and reducing the risk of runtime errors.
  */
 
+// interface implemented by all queues of this package
+type Queuer[T any] interface {
+	Add(item T)
+	Next() (T, bool)
+	Peek() (T, bool)
+	IsEmpty() bool
+}
+
+// all implementations can be used interchangeably
+var (
+	_ Queuer[int] = (*Queue[int])(nil)
+	_ Queuer[int] = (*Circular[int])(nil)
+	_ Queuer[int] = (*Linked[int])(nil)
+	_ Queuer[int] = (*TwoStack[int])(nil)
+	_ Queuer[int] = (*Sharded[int])(nil)
+	_ Queuer[int] = (*COW[int])(nil)
+)
+
 // generic queue structure
 type Queue[T any] struct {
-	items []T
+	items  []T
+	growth growth.Policy
 }
 
 // create a new queue
@@ -19,9 +46,14 @@ func New[T any]() *Queue[T] {
 	return &Queue[T]{}
 }
 
+// create a new queue growing according to policy
+func NewWithGrowth[T any](policy growth.Policy) *Queue[T] {
+	return &Queue[T]{growth: policy}
+}
+
 // add item to the top of queue
 func (q *Queue[T]) Add(item T) {
-	q.items = append(q.items, item)
+	q.items = growth.Push(q.growth, q.items, item)
 }
 
 // remove and return from top of the stack
@@ -48,3 +80,59 @@ func (q *Queue[T]) Peek() (T, bool) {
 func (q *Queue[T]) IsEmpty() bool {
 	return len(q.items) == 0
 }
+
+// number of elements shown by String before eliding the middle
+const maxShown = 8
+
+// short description like "Queue[int] len=21 next=145 [5 12 19 ... 131 138 145]"
+func (q *Queue[T]) String() string {
+	var zero T
+	var b strings.Builder
+	fmt.Fprintf(&b, "Queue[%T] len=%d", zero, len(q.items))
+	if len(q.items) > 0 {
+		fmt.Fprintf(&b, " next=%v", q.items[0])
+	}
+	if len(q.items) > maxShown {
+		fmt.Fprintf(&b, " [%s ... %s]", format(q.items[:maxShown/2], " ", nil), format(q.items[len(q.items)-maxShown/2:], " ", nil))
+	} else {
+		fmt.Fprintf(&b, " [%s]", q.Format(" ", nil))
+	}
+	return b.String()
+}
+
+// all elements from front to back converted by f (fmt.Sprint if nil), joined by sep
+func (q *Queue[T]) Format(sep string, f func(T) string) string {
+	return format(q.items, sep, f)
+}
+
+// items converted by f (fmt.Sprint if nil), joined by sep
+func format[T any](items []T, sep string, f func(T) string) string {
+	if f == nil {
+		f = func(item T) string { return fmt.Sprint(item) }
+	}
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(f(item))
+	}
+	return b.String()
+}
+
+// write all elements to w, one per line
+func (q *Queue[T]) Dump(w io.Writer) error {
+	var zero T
+	if _, err := fmt.Fprintf(w, "Queue[%T] len=%d\n", zero, len(q.items)); err != nil {
+		return err
+	}
+	if len(q.items) == 0 {
+		return nil
+	}
+	i := 0
+	_, err := fmt.Fprintln(w, q.Format("\n", func(item T) string {
+		i++
+		return fmt.Sprintf("%d: %v", i-1, item)
+	}))
+	return err
+}
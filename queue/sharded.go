@@ -0,0 +1,125 @@
+package queue
+
+import (
+	"hash/maphash"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/juli-99/hka-modell_basierte_software/partition"
+)
+
+/* A sharded queue spreads its items over several independently locked
+ * queues, so producers adding items with different keys rarely contend
+ * for the same lock. Items with the same key always land in the same
+ * shard (chosen by consistent hashing) and keep their FIFO order;
+ * across shards there is no order.
+ * Next scans the shards round-robin, starting one shard further each call,
+ * so no shard is starved while others have items.
+ */
+
+// single synchronized queue inside a Sharded queue
+type shard[T any] struct {
+	mu sync.Mutex
+	q  Queue[T]
+}
+
+// generic sharded queue structure, safe for concurrent use
+type Sharded[T any] struct {
+	shards []shard[T]
+	hash   func(T) uint64
+	ring   *partition.Ring
+	next   atomic.Uint64 // shard the next scan starts at
+	size   atomic.Int64
+}
+
+// create a new Sharded queue with n shards, items are assigned by key
+func NewSharded[T any, K comparable](n int, key func(T) K) *Sharded[T] {
+	if n < 1 {
+		panic("queue: number of shards must be positive")
+	}
+	seed := maphash.MakeSeed()
+	return &Sharded[T]{
+		shards: make([]shard[T], n),
+		ring:   partition.New(n, partition.DefaultVirtualNodes),
+		hash: func(item T) uint64 {
+			return maphash.Comparable(seed, key(item))
+		},
+	}
+}
+
+// add item to the end of its shard
+func (s *Sharded[T]) Add(item T) {
+	sh := &s.shards[s.ring.Get(s.hash(item))]
+	sh.mu.Lock()
+	sh.q.Add(item)
+	s.size.Add(1)
+	sh.mu.Unlock()
+}
+
+// remove and return the first item of the next non-empty shard
+func (s *Sharded[T]) Next() (T, bool) {
+	start := s.next.Add(1)
+	for i := range uint64(len(s.shards)) {
+		sh := &s.shards[(start+i)%uint64(len(s.shards))]
+		sh.mu.Lock()
+		item, ok := sh.q.Next()
+		if ok {
+			s.size.Add(-1)
+		}
+		sh.mu.Unlock()
+		if ok {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false // return default value and false if all shards are empty
+}
+
+// return the first item of the next non-empty shard without removing it
+func (s *Sharded[T]) Peek() (T, bool) {
+	start := s.next.Load() + 1
+	for i := range uint64(len(s.shards)) {
+		sh := &s.shards[(start+i)%uint64(len(s.shards))]
+		sh.mu.Lock()
+		item, ok := sh.q.Peek()
+		sh.mu.Unlock()
+		if ok {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false // return default value and false if all shards are empty
+}
+
+// number of items in all shards
+func (s *Sharded[T]) Len() int {
+	return int(s.size.Load())
+}
+
+// checks if all shards are empty
+func (s *Sharded[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// copy of all items, shard by shard in FIFO order;
+// all shards are locked at once, so the copy is consistent
+func (s *Sharded[T]) Snapshot() []T {
+	for i := range s.shards {
+		s.shards[i].mu.Lock()
+	}
+	items := make([]T, 0, s.Len())
+	for i := range s.shards {
+		items = append(items, s.shards[i].q.items...)
+	}
+	for i := range s.shards {
+		s.shards[i].mu.Unlock()
+	}
+	return items
+}
+
+// iterate over a snapshot of all shards
+func (s *Sharded[T]) All() iter.Seq[T] {
+	return slices.Values(s.Snapshot())
+}
@@ -0,0 +1,53 @@
+package queue
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+)
+
+// sort the queue in place (stable) so that Next returns the smallest item first
+func (q *Queue[T]) Sort(less func(a, b T) bool) {
+	slices.SortStableFunc(q.items, func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+}
+
+// reports whether Next returns the items in ascending order of less
+func (q *Queue[T]) IsSorted(less func(a, b T) bool) bool {
+	for i := 1; i < len(q.items); i++ {
+		if less(q.items[i], q.items[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// put the items in a random order drawn from r, the same seed gives the same order
+func (q *Queue[T]) Shuffle(r *rand.Rand) {
+	r.Shuffle(len(q.items), func(i, j int) {
+		q.items[i], q.items[j] = q.items[j], q.items[i]
+	})
+}
+
+/* SearchFunc relies on the order established by Sort: it finds the
+ * first item not less than target with a binary search in O(log n).
+ * If the queue is not sorted by less the index is still in [0, len], but
+ * meaningless; a reported presence is always right (the item at the index
+ * is equal to target), a missing one may be wrong.
+ */
+
+// position of target counted from the front of a queue sorted by less
+// (or where it would be inserted), and whether it is present
+func (q *Queue[T]) SearchFunc(target T, less func(a, b T) bool) (int, bool) {
+	i := sort.Search(len(q.items), func(i int) bool {
+		return !less(q.items[i], target)
+	})
+	return i, i < len(q.items) && !less(target, q.items[i])
+}
@@ -0,0 +1,57 @@
+package queue
+
+import "github.com/juli-99/hka-modell_basierte_software/stack"
+
+/* The classic queue made of two stacks: Add pushes onto the input stack,
+ * Next pops from the output stack. Only when the output stack is empty,
+ * all items are moved over from the input stack, which reverses their
+ * order and puts the oldest item on top.
+ * A single Next can cost O(n) moves, but every item is moved at most once
+ * in its lifetime, so n operations cost O(n) in total: amortized O(1).
+ * The moves are counted, so a test can observe this bound:
+ * after n Adds there are never more than n.
+ */
+
+// generic two-stack queue structure
+type TwoStack[T any] struct {
+	in, out *stack.Stack[T]
+	moves   int // items moved from the input to the output stack so far
+}
+
+// create a new TwoStack queue
+func NewTwoStack[T any]() *TwoStack[T] {
+	return &TwoStack[T]{in: stack.New[T](), out: stack.New[T]()}
+}
+
+// add item to the end of queue
+func (q *TwoStack[T]) Add(item T) {
+	q.in.Push(item)
+}
+
+// move all items to the output stack if it is empty, reversing their order
+func (q *TwoStack[T]) refill() {
+	if !q.out.IsEmpty() {
+		return
+	}
+	for item, ok := q.in.Pop(); ok; item, ok = q.in.Pop() {
+		q.out.Push(item)
+		q.moves++
+	}
+}
+
+// remove and return from the front of the queue
+func (q *TwoStack[T]) Next() (T, bool) {
+	q.refill()
+	return q.out.Pop()
+}
+
+// return from the front of the queue
+func (q *TwoStack[T]) Peek() (T, bool) {
+	q.refill()
+	return q.out.Peek()
+}
+
+// checks if the queue is empty
+func (q *TwoStack[T]) IsEmpty() bool {
+	return q.in.IsEmpty() && q.out.IsEmpty()
+}
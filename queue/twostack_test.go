@@ -0,0 +1,34 @@
+package queue
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// n mixed Add and Next calls keep FIFO order and cost at most 2n moves
+func TestTwoStackAmortized(t *testing.T) {
+	const n = 10000
+	r := rand.New(rand.NewPCG(1, 0))
+	q := NewTwoStack[int]()
+	var want []int
+	for i := range n {
+		if r.IntN(3) > 0 {
+			q.Add(i)
+			want = append(want, i)
+			continue
+		}
+		item, ok := q.Next()
+		if ok != (len(want) > 0) {
+			t.Fatalf("Next ok = %v with %d items", ok, len(want))
+		}
+		if ok {
+			if item != want[0] {
+				t.Fatalf("Next = %d, want %d", item, want[0])
+			}
+			want = want[1:]
+		}
+	}
+	if q.moves > 2*n {
+		t.Fatalf("%d moves for %d operations, want at most %d", q.moves, n, 2*n)
+	}
+}
@@ -0,0 +1,118 @@
+package reduce
+
+import (
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* Reducers are sinks folding every result into a single accumulated
+ * value, e.g. a count, a sum or counts per key. The accumulator type A
+ * is a type parameter of its own, so a Sum over ints stays an int and a
+ * group-by keeps the key type of the caller.
+ * A reducer is a pipeline sink and is read with Value after Run returned;
+ * combined with Where it only sees the results it is interested in.
+ */
+
+// numeric types that can be summed and averaged
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// generic reducing sink structure
+type Reducer[T, R, A any] struct {
+	mu   sync.Mutex
+	acc  A
+	step func(acc A, res pool.Result[T, R]) A
+}
+
+// create a new Reducer starting with init and folding every result with step
+func New[T, R, A any](init A, step func(acc A, res pool.Result[T, R]) A) *Reducer[T, R, A] {
+	return &Reducer[T, R, A]{acc: init, step: step}
+}
+
+func (r *Reducer[T, R, A]) Put(res pool.Result[T, R]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.acc = r.step(r.acc, res)
+	return nil
+}
+
+func (r *Reducer[T, R, A]) Close() error {
+	return nil
+}
+
+// accumulated value, maps and slices are shared with the reducer
+// and must only be read once the pipeline is done
+func (r *Reducer[T, R, A]) Value() A {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.acc
+}
+
+// number of results
+func Count[T, R any]() *Reducer[T, R, int] {
+	return New(0, func(n int, _ pool.Result[T, R]) int {
+		return n + 1
+	})
+}
+
+// sum of value over all results
+func Sum[T, R any, N Number](value func(res pool.Result[T, R]) N) *Reducer[T, R, N] {
+	return New(0, func(sum N, res pool.Result[T, R]) N {
+		return sum + value(res)
+	})
+}
+
+// running mean
+type Mean struct {
+	Sum   float64
+	Count int
+}
+
+// mean of all values, 0 without values
+func (m Mean) Value() float64 {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.Sum / float64(m.Count)
+}
+
+// average of value over all results
+func Average[T, R any, N Number](value func(res pool.Result[T, R]) N) *Reducer[T, R, Mean] {
+	return New(Mean{}, func(m Mean, res pool.Result[T, R]) Mean {
+		return Mean{Sum: m.Sum + float64(value(res)), Count: m.Count + 1}
+	})
+}
+
+// number of results per key
+func GroupByKey[T, R any, K comparable](key func(res pool.Result[T, R]) K) *Reducer[T, R, map[K]int] {
+	return New(make(map[K]int), func(counts map[K]int, res pool.Result[T, R]) map[K]int {
+		counts[key(res)]++
+		return counts
+	})
+}
+
+// sink passing only the results for which keep holds on to s
+func Where[T, R any](keep func(res pool.Result[T, R]) bool, s pipeline.Sink[T, R]) pipeline.Sink[T, R] {
+	return &whereSink[T, R]{keep: keep, sink: s}
+}
+
+type whereSink[T, R any] struct {
+	keep func(res pool.Result[T, R]) bool
+	sink pipeline.Sink[T, R]
+}
+
+func (s *whereSink[T, R]) Put(res pool.Result[T, R]) error {
+	if !s.keep(res) {
+		return nil
+	}
+	return s.sink.Put(res)
+}
+
+func (s *whereSink[T, R]) Close() error {
+	return s.sink.Close()
+}
@@ -0,0 +1,192 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+)
+
+/* A tiny request/response protocol over TCP so items can be validated by
+ * worker processes on other machines. Every message is a frame made of a
+ * 4 byte big-endian length followed by the payload. A request carries the
+ * encoded item, a response starts with a status byte followed by either
+ * the encoded result or an error message.
+ * The codecs are generic over the item and result type, so client and
+ * server of the same T and R can not disagree on the wire format at
+ * compile time.
+ */
+
+const (
+	statusOK    byte = 0
+	statusError byte = 1
+)
+
+// largest frame accepted from the network
+const maxFrame = 16 * 1024 * 1024
+
+// error returned for frames exceeding maxFrame
+var ErrFrameTooLarge = errors.New("remote: frame too large")
+
+// error reported by the remote worker
+type RemoteError struct {
+	Msg string
+}
+
+func (e *RemoteError) Error() string {
+	return "remote: " + e.Msg
+}
+
+// write a length-prefixed frame
+func writeFrame(w io.Writer, payload []byte) error {
+	var head [4]byte
+	binary.BigEndian.PutUint32(head[:], uint32(len(payload)))
+	if _, err := w.Write(head[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// read a length-prefixed frame
+func readFrame(r io.Reader) ([]byte, error) {
+	var head [4]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(head[:])
+	if n > maxFrame {
+		return nil, ErrFrameTooLarge
+	}
+	payload := make([]byte, n)
+	_, err := io.ReadFull(r, payload)
+	return payload, err
+}
+
+// serve fn on every connection accepted from l until l is closed
+func Serve[T, R any](l net.Listener, fn func(T) R, in codec.Codec[T], out codec.Codec[R]) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go serveConn(conn, fn, in, out)
+	}
+}
+
+// answer requests on a single connection until the client hangs up
+func serveConn[T, R any](conn net.Conn, fn func(T) R, in codec.Codec[T], out codec.Codec[R]) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		req, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		resp := []byte{statusOK}
+		item, err := in.Decode(req)
+		if err == nil {
+			var data []byte
+			data, err = out.Encode(fn(item))
+			resp = append(resp, data...)
+		}
+		if err != nil {
+			resp = append([]byte{statusError}, err.Error()...)
+		}
+		if err := writeFrame(conn, resp); err != nil {
+			return
+		}
+	}
+}
+
+// client structure sending items to a remote worker
+type Client[T, R any] struct {
+	addr  string
+	in    codec.Codec[T]
+	out   codec.Codec[R]
+	conns chan net.Conn // idle connections
+}
+
+// connect to the worker at addr, conns limits the number of kept idle connections
+func Dial[T, R any](addr string, conns int, in codec.Codec[T], out codec.Codec[R]) (*Client[T, R], error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client[T, R]{
+		addr:  addr,
+		in:    in,
+		out:   out,
+		conns: make(chan net.Conn, max(conns, 1)),
+	}
+	c.conns <- conn
+	return c, nil
+}
+
+// send item to the remote worker and wait for its result,
+// safe for concurrent use (each call uses its own connection)
+func (c *Client[T, R]) Call(item T) (R, error) {
+	var zero R
+	req, err := c.in.Encode(item)
+	if err != nil {
+		return zero, err
+	}
+	conn, err := c.get()
+	if err != nil {
+		return zero, err
+	}
+	if err := writeFrame(conn, req); err != nil {
+		conn.Close()
+		return zero, err
+	}
+	resp, err := readFrame(conn)
+	if err != nil || len(resp) == 0 {
+		conn.Close()
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		return zero, err
+	}
+	c.put(conn)
+	if resp[0] != statusOK {
+		return zero, &RemoteError{Msg: string(resp[1:])}
+	}
+	return c.out.Decode(resp[1:])
+}
+
+// take an idle connection or dial a new one
+func (c *Client[T, R]) get() (net.Conn, error) {
+	select {
+	case conn := <-c.conns:
+		return conn, nil
+	default:
+		return net.Dial("tcp", c.addr)
+	}
+}
+
+// return a connection to the idle list or close it if the list is full
+func (c *Client[T, R]) put(conn net.Conn) {
+	select {
+	case c.conns <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// close all idle connections
+func (c *Client[T, R]) Close() error {
+	for {
+		select {
+		case conn := <-c.conns:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
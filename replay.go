@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/bus"
+	"github.com/juli-99/hka-modell_basierte_software/trace"
+)
+
+// replay a trace recorded with run -trace and print its events,
+// check it against a golden file or draw it as a diagram
+func replayCmd(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := fs.Float64("speed", 1, "replay speed, 2 is twice as fast, 0 without delays")
+	golden := fs.String("golden", "", "compare the trace with this golden file instead of replaying it")
+	mermaid := fs.Int("mermaid", 0, "print a Mermaid sequence diagram of the first n items instead of replaying")
+	update := fs.Bool("update", false, "with -golden, write the golden file from the trace")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: replay [-speed n] [-golden file [-update]] [-mermaid n] trace.jsonl")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *mermaid > 0 {
+		events, err := trace.Read(f)
+		if err != nil {
+			return err
+		}
+		return trace.Mermaid(os.Stdout, events, *mermaid)
+	}
+	if *golden != "" {
+		events, err := trace.Read(f)
+		if err != nil {
+			return err
+		}
+		if err := trace.Golden(*golden, events, *update); err != nil {
+			return err
+		}
+		if *update {
+			fmt.Printf("updated %s with %d events\n", *golden, len(events))
+			return nil
+		}
+		fmt.Printf("%d events match %s\n", len(events), *golden)
+		return nil
+	}
+
+	events := bus.New[trace.Event]()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	sub := events.SubscribeAll() // a replay must not lose events
+	go func() {
+		defer wg.Done()
+		for e := range sub.C() {
+			if e.Err != "" {
+				fmt.Printf("%8v %s %s: item: %s error: %s\n", e.At.Round(1e6), e.Pipeline, e.WorkerName(), e.Item, e.Err)
+				continue
+			}
+			fmt.Printf("%8v %s %s: item: %s result: %s\n", e.At.Round(1e6), e.Pipeline, e.WorkerName(), e.Item, e.Value)
+		}
+	}()
+	err = trace.Replay(f, *speed, events)
+	events.Close()
+	wg.Wait()
+	return err
+}
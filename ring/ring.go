@@ -0,0 +1,79 @@
+package ring
+
+/* A ring buffer keeps the last n elements pushed into it.
+ * When it is full, every Push overwrites the oldest element,
+ * so memory stays bounded no matter how many elements pass through.
+ * That is the typical behavior needed for telemetry like "last results".
+ */
+
+// generic fixed-size ring buffer structure
+type Ring[T any] struct {
+	items []T
+	start int // index of the oldest element
+	n     int // number of stored elements
+}
+
+// create a new Ring holding at most size elements
+func New[T any](size int) *Ring[T] {
+	if size < 1 {
+		panic("ring: size must be positive")
+	}
+	return &Ring[T]{items: make([]T, size)}
+}
+
+// add item as newest element, returns the overwritten oldest element and true if the ring was full
+func (r *Ring[T]) Push(item T) (T, bool) {
+	if r.n < len(r.items) {
+		r.items[(r.start+r.n)%len(r.items)] = item
+		r.n++
+		var zero T
+		return zero, false
+	}
+	old := r.items[r.start]
+	r.items[r.start] = item
+	r.start = (r.start + 1) % len(r.items)
+	return old, true
+}
+
+// return the oldest element
+func (r *Ring[T]) Oldest() (T, bool) {
+	if r.n == 0 {
+		var zero T
+		return zero, false // return default value and false if ring is empty
+	}
+	return r.items[r.start], true
+}
+
+// return the newest element
+func (r *Ring[T]) Newest() (T, bool) {
+	if r.n == 0 {
+		var zero T
+		return zero, false // return default value and false if ring is empty
+	}
+	return r.items[(r.start+r.n-1)%len(r.items)], true
+}
+
+// number of stored elements
+func (r *Ring[T]) Len() int {
+	return r.n
+}
+
+// maximum number of stored elements
+func (r *Ring[T]) Cap() int {
+	return len(r.items)
+}
+
+// copy of the elements ordered from oldest to newest
+func (r *Ring[T]) Snapshot() []T {
+	out := make([]T, r.n)
+	for i := range out {
+		out[i] = r.items[(r.start+i)%len(r.items)]
+	}
+	return out
+}
+
+// remove all elements
+func (r *Ring[T]) Reset() {
+	clear(r.items)
+	r.start, r.n = 0, 0
+}
@@ -0,0 +1,928 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juli-99/hka-modell_basierte_software/bloom"
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+	"github.com/juli-99/hka-modell_basierte_software/collect"
+	"github.com/juli-99/hka-modell_basierte_software/color"
+	"github.com/juli-99/hka-modell_basierte_software/config"
+	"github.com/juli-99/hka-modell_basierte_software/group"
+	"github.com/juli-99/hka-modell_basierte_software/i18n"
+	"github.com/juli-99/hka-modell_basierte_software/manager"
+	"github.com/juli-99/hka-modell_basierte_software/memo"
+	"github.com/juli-99/hka-modell_basierte_software/model"
+	"github.com/juli-99/hka-modell_basierte_software/multiset"
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+	"github.com/juli-99/hka-modell_basierte_software/queue"
+	"github.com/juli-99/hka-modell_basierte_software/reduce"
+	"github.com/juli-99/hka-modell_basierte_software/remote"
+	"github.com/juli-99/hka-modell_basierte_software/source"
+	"github.com/juli-99/hka-modell_basierte_software/ticker"
+	"github.com/juli-99/hka-modell_basierte_software/trace"
+	"github.com/juli-99/hka-modell_basierte_software/validate"
+	"github.com/juli-99/hka-modell_basierte_software/wal"
+)
+
+// line written to file outputs
+type resultLine[T any] struct {
+	ID        uint64    `json:"id"`
+	Submitted time.Time `json:"submitted"`
+	Worker    int       `json:"worker"`
+	Name      string    `json:"worker_name"`
+	Item      T         `json:"item"`
+	Valid     bool      `json:"valid"`
+}
+
+// run the configured validation pipelines side by side
+func runCmd(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	config_path := fs.String("config", "", "pipeline configuration (JSON), defaults to the built-in demo")
+	remote_addr := fs.String("remote", "", "validate the ints on the remote worker at this address")
+	timeout := fs.Duration("timeout", 0, "abort each pipeline after this duration with partial results (0 = no limit)")
+	item_timeout := fs.Duration("item-timeout", 0, "count an item as invalid if its validation takes longer than this (0 = off)")
+	stuck := fs.Duration("stuck", 0, "report items still running after this duration as failed (0 = off)")
+	trace_path := fs.String("trace", "", "record the events of all pipelines to this file (see replay)")
+	check := fs.Duration("check", 0, "check the pipeline invariants at this interval (0 = off)")
+	group_mod := fs.Int("group-mod", 0, "group the valid ints by their remainder modulo n in the report (0 = off)")
+	top := fs.Int("top", 0, "report the k greatest valid items of every pipeline (0 = off)")
+	sample := fs.Int("sample", 0, "report a random sample of n processed items of every pipeline (0 = off)")
+	bloom_fpr := new(float64)
+	fs.Func("bloom", "flag probable duplicate items with a Bloom filter of this false-positive rate, between 0 and 1 (default off)", func(arg string) error {
+		p, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return err
+		}
+		if p != 0 && !(p > 0 && p < 1) {
+			return bloom.ErrRate
+		}
+		*bloom_fpr = p
+		return nil
+	})
+	dot_path := fs.String("dot", "", "write a Graphviz graph of every pipeline to this file")
+	pprof_addr := fs.String("pprof", "", "serve net/http/pprof on this address while running")
+	stats_addr := fs.String("stats", "", "serve the live figures of all pipelines as JSON on GET /stats at this address while running")
+	cpu_profile := fs.String("cpuprofile", "", "write a CPU profile of the run to this file")
+	mem_profile := fs.String("memprofile", "", "write a heap profile at the end of the run to this file")
+	budget := fs.Int("budget", 0, "workers shared by all pipelines (0 = sum of the configured workers)")
+	seed := fs.Uint64("seed", defaultSeed, "seed of -random, -sample and -jitter, the same seed reproduces the same run")
+	memoize := fs.Bool("memo", false, "validate every distinct item only once and reuse the verdict for duplicates")
+	shuffle := fs.Bool("shuffle", false, "process the configured items of every pipeline in a random order drawn from -seed")
+	random := fs.Int("random", 0, "validate n pseudorandom items per pipeline instead of the configured input (0 = off)")
+	every := fs.Duration("every", 0, "submit the configured items of every pipeline round-robin, one per interval, until Ctrl-C or -timeout ends the run (0 = all at once)")
+	jitter := fs.Duration("jitter", 0, "randomize every -every interval by up to ± this duration, drawn from -seed")
+	fail_threshold := fs.Float64("fail-threshold", 1, "exit with code 3 if the fraction of invalid items of a pipeline exceeds this (1 = never)")
+	lang := fs.String("lang", string(i18n.English), "language of the item lines and the summary (en, de)")
+	no_color := fs.Bool("no-color", false, "never color the item lines (default: color on a terminal)")
+	quiet := fs.Bool("quiet", false, "do not print a line per item")
+	as_json := fs.Bool("json", false, "print the final summary as a single JSON object (combine with -quiet for JSON only)")
+	feedback := fs.Int("feedback", 0, "normalize invalid strings (trim, collapse spaces, capitalize words) and validate them again, up to n times")
+	join := fs.Bool("join", false, "pair the results of the first int and the first string pipeline with the same id (the n-th submitted item of each) and summarize the pairs")
+	interactive := fs.Bool("interactive", false, "read the items of the first pipeline from stdin, one per line, and echo every result")
+	fs.Parse(args)
+
+	stop_profiling, err := startProfiling(*pprof_addr, *cpu_profile, *mem_profile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stop_profiling(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}()
+
+	msg, err := i18n.New(i18n.Lang(*lang))
+	if err != nil {
+		return err
+	}
+
+	paint := color.New(!*no_color && color.Auto(os.Stdout))
+
+	cfg := config.Default()
+	if *config_path != "" {
+		var err error
+		if cfg, err = config.Load(*config_path); err != nil {
+			return err
+		}
+	}
+
+	var client *remote.Client[int, bool]
+	if *remote_addr != "" {
+		var err error
+		client, err = remote.Dial(*remote_addr, config.DefaultWorkers, codec.JSON[int](), codec.JSON[bool]())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+	}
+
+	if *budget <= 0 {
+		*budget = 0
+		for _, p := range cfg.Pipelines {
+			*budget += p.Workers
+		}
+	}
+	mgr := manager.New(*budget)
+
+	var stats *statsServer
+	if *stats_addr != "" {
+		stats = newStatsServer()
+		stop_stats, err := stats.listen(*stats_addr)
+		if err != nil {
+			return err
+		}
+		defer stop_stats()
+	}
+
+	var dot *lockedWriter
+	if *dot_path != "" {
+		f, err := os.Create(*dot_path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		dot = &lockedWriter{w: f}
+	}
+
+	var rec *trace.Recorder
+	if *trace_path != "" {
+		f, err := os.Create(*trace_path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rec = trace.NewRecorder(f)
+	}
+
+	// The join gets the results of the first int and the first string pipeline
+	var joined, both_valid int
+	var int_join pipeline.Sink[int, bool]
+	var string_join pipeline.Sink[string, bool]
+	if *join {
+		j := pipeline.NewJoin(pipeline.ByID[int, bool], pipeline.ByID[string, bool], func(pair pipeline.Pair[int, bool, string, bool]) {
+			joined++
+			if pair.Left.Value && pair.Right.Value {
+				both_valid++
+			}
+		})
+		int_join, string_join = j.Left(), j.Right()
+	}
+
+	// Resolve all validators first, so a typo does not leave other pipelines half done
+	var runs []func(ctx context.Context) (*report, error)
+	for i, p := range cfg.Pipelines {
+		opts := runOptions{dot: dot, top: *top, sample: *sample, bloom: *bloom_fpr, timeout: *timeout, stuck: *stuck, item_timeout: *item_timeout, mgr: mgr, rec: rec, stats: stats, check: *check > 0, msg: msg, paint: paint, quiet: *quiet, interactive: *interactive && i == 0, feedback: *feedback, random: *random, every: *every, jitter: *jitter, shuffle: *shuffle, memo: *memoize, seed: *seed + uint64(i)}
+		switch p.Type {
+		case config.TypeInt:
+			validator, err := lookupValidator(p.Validator, codec.Int())
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				// Dispatch to the remote worker, failed calls count as invalid
+				validator = func(n int) bool {
+					valid, err := client.Call(n)
+					if err != nil && !*quiet {
+						msg.Fprintf(os.Stdout, "remote: item: %v error: %v\n", n, err)
+						return false
+					}
+					return valid
+				}
+			}
+			var group func(int) string
+			if *group_mod > 0 {
+				group = func(n int) string {
+					return fmt.Sprintf("n%%%d=%d", *group_mod, n%*group_mod)
+				}
+			}
+			ty := typed[int]{group: group, less: cmp.Less[int], random: randomInt, join: int_join}
+			int_join = nil
+			runs = append(runs, func(ctx context.Context) (*report, error) {
+				return runPipeline(ctx, p, codec.Int(), validator, ty, opts)
+			})
+		case config.TypeString:
+			validator, err := lookupValidator(p.Validator, codec.String())
+			if err != nil {
+				return err
+			}
+			ty := typed[string]{less: cmp.Less[string], random: randomString, join: string_join, normalize: normalizeString}
+			string_join = nil
+			runs = append(runs, func(ctx context.Context) (*report, error) {
+				return runPipeline(ctx, p, codec.String(), validator, ty, opts)
+			})
+		}
+	}
+
+	/* All pipelines run at the same time, each with its configured
+	 * workers, and share the worker budget of the manager, which allots
+	 * the slots by pending load.
+	 * Like an errgroup the first failing pipeline cancels the others,
+	 * their partial summaries are still printed.
+	 */
+	if *check > 0 {
+		watch_ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go model.Watch(watch_ctx, *check, func(v *model.Violation) {
+			fmt.Fprintln(os.Stderr, v)
+		})
+	}
+
+	g, ctx := group.WithContext(context.Background())
+	reports := make([]*report, len(runs))
+	for i, run := range runs {
+		g.Go(func() error {
+			var err error
+			reports[i], err = run(ctx)
+			return err
+		})
+	}
+	first := g.Wait()
+
+	// Report all pipelines in configured order once everything is done
+	if *as_json {
+		var out struct {
+			Pipelines []summary `json:"pipelines"`
+		}
+		for _, r := range reports {
+			if r != nil {
+				out.Pipelines = append(out.Pipelines, r.summary)
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range reports {
+			if r != nil {
+				fmt.Print(r.text)
+			}
+		}
+		var counts []string
+		for _, r := range reports {
+			if r != nil {
+				counts = append(counts, fmt.Sprintf("%s=%d", r.Name, r.Valid))
+			}
+		}
+		msg.Fprintf(os.Stdout, "Valid items: %s\n", strings.Join(counts, " "))
+		if *join {
+			msg.Fprintf(os.Stdout, "Joined pairs: %d, both valid: %d\n", joined, both_valid)
+		}
+	}
+	if first != nil {
+		return first
+	}
+	for _, r := range reports {
+		if r.Total > 0 && float64(r.Total-r.Valid)/float64(r.Total) > *fail_threshold {
+			return &thresholdError{name: r.Name, invalid: r.Total - r.Valid, total: r.Total, threshold: *fail_threshold}
+		}
+	}
+	return nil
+}
+
+// summary of a finished pipeline, printed as text or JSON (-json)
+type report struct {
+	summary
+	text string
+}
+
+// figures of a finished pipeline
+type summary struct {
+	Name       string         `json:"name"`
+	Total      int            `json:"total"`      // processed items
+	Valid      int            `json:"valid"`      // items the validator accepted
+	Failed     int            `json:"failed"`     // items whose validation failed with an error
+	Workers    map[string]int `json:"workers"`    // results per worker name
+	Throughput float64        `json:"throughput"` // items/s over the last pool.RateWindow
+	Latency    latency        `json:"latency"`
+}
+
+// latency percentiles in nanoseconds
+type latency struct {
+	P50 time.Duration `json:"p50_ns"`
+	P95 time.Duration `json:"p95_ns"`
+	P99 time.Duration `json:"p99_ns"`
+}
+
+// error of a run whose pipeline had too many invalid items
+type thresholdError struct {
+	name           string
+	invalid, total int
+	threshold      float64
+}
+
+func (e *thresholdError) Error() string {
+	return fmt.Sprintf("pipeline %s: %d of %d items invalid, more than the threshold of %g", e.name, e.invalid, e.total, e.threshold)
+}
+
+func (e *thresholdError) ExitCode() int {
+	return exitInvalid
+}
+
+// prefix of validators running an external command per item, e.g. "exec:./check.sh -v"
+const execValidator = "exec:"
+
+// time an external validator may run per item before the item counts as invalid
+const execTimeout = 10 * time.Second
+
+// validator of a pipeline config: the external command after
+// execValidator (see validate.Exec), otherwise a registered one
+func lookupValidator[T any](name string, c codec.Codec[T]) (validate.Validator[T], error) {
+	command, ok := strings.CutPrefix(name, execValidator)
+	if !ok {
+		return validate.Lookup[T](name)
+	}
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("validate: %q names no command", name)
+	}
+	return validate.Exec(c, runtime.NumCPU(), execTimeout, args[0], args[1:]...)
+}
+
+// prefix of the worker names of a pipeline, e.g. "worker-ints-"
+func workerPrefix(p config.Pipeline) string {
+	return pool.DefaultNamePrefix + p.Name + "-"
+}
+
+// settings of a single pipeline run taken from the command line
+type runOptions struct {
+	timeout      time.Duration // abort the run after this duration, 0 = no limit
+	stuck        time.Duration // stuck-worker detection interval, 0 = off
+	item_timeout time.Duration // validation time after which an item is invalid, 0 = off
+	mgr          *manager.Manager
+	rec          *trace.Recorder // nil without -trace
+	stats        *statsServer    // nil without -stats
+	check        bool            // register the invariants of the pipeline
+	top          int             // number of greatest valid items reported
+	sample       int             // number of processed items sampled for the report
+	bloom        float64         // false-positive rate of the duplicate filter, 0 = off
+	dot          *lockedWriter   // receives the Graphviz graph, nil without -dot
+
+	msg         *i18n.Printer
+	paint       color.Painter // colors of the item lines
+	quiet       bool          // no console output per item
+	interactive bool          // items are typed on stdin instead of the configured input
+	feedback    int           // cycles of -feedback, 0 = off
+	random      int           // number of generated items replacing the configured input, 0 = off
+	every       time.Duration // interval of the ticker submitting the configured items, 0 = off
+	jitter      time.Duration // randomization of every interval of the ticker
+	shuffle     bool          // configured items are processed in a random order
+	memo        bool          // verdicts are remembered per distinct item
+	seed        uint64        // seed of the generated items and the sample
+}
+
+// writer shared by concurrent pipelines, every Write is written in one piece
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+/* -random and -sample only use generators seeded from -seed (plus the
+ * index of the pipeline, so pipelines of the same type differ), so the
+ * same command line yields the same items and the same report on every
+ * machine, and the output can be graded against an expected one.
+ * Only the interleaving of the per-item lines depends on the scheduler.
+ */
+
+// seed used without -seed
+const defaultSeed = 1
+
+// settings of a pipeline that depend on its element type
+type typed[T any] struct {
+	group  func(T) string         // bucket of a valid item in the report, nil = off
+	less   func(a, b T) bool      // order of the items for -top
+	random func(r *rand.Rand) T   // item generator for -random
+	join   pipeline.Sink[T, bool] // side of the -join, nil = not joined
+	// item to validate again for -feedback, false if it can not be improved
+	normalize func(T) (T, bool)
+}
+
+// s with trimmed and collapsed spaces and capitalized words,
+// false if that does not change s
+func normalizeString(s string) (string, bool) {
+	words := strings.Fields(s)
+	for i, w := range words {
+		first, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(first)) + w[size:]
+	}
+	normalized := strings.Join(words, " ")
+	return normalized, normalized != s
+}
+
+// pseudorandom int in [0, 1000)
+func randomInt(r *rand.Rand) int {
+	return r.IntN(1000)
+}
+
+// words random strings are made of
+var randomWords = []string{"Hello", "World", "Generics", "Wide", "Web", "Type", "Set", "Go"}
+
+// pseudorandom string of one to three words
+func randomString(r *rand.Rand) string {
+	words := make([]string, 1+r.IntN(3))
+	for i := range words {
+		words[i] = randomWords[r.IntN(len(randomWords))]
+	}
+	return strings.Join(words, " ")
+}
+
+/* runPipeline is generic so the same code drives the int and the string
+ * pipeline; the codec, the queue, the pool and the validation function
+ * are all bound to the same T by the compiler.
+ */
+func runPipeline[T comparable](ctx context.Context, p config.Pipeline, c codec.Codec[T], validator func(T) bool, ty typed[T], opts runOptions) (*report, error) {
+	items, err := loadItems(p.Input, c)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline %s: %w", p.Name, err)
+	}
+	if opts.interactive || opts.random > 0 {
+		items = nil
+	}
+	if opts.interactive {
+		if !slices.Contains(p.Outputs, config.Output{Type: config.OutputConsole}) {
+			p.Outputs = append(slices.Clip(p.Outputs), config.Output{Type: config.OutputConsole})
+		}
+	}
+	impl := queue.ImplSlice
+	if p.Queue != "" {
+		if impl, err = queue.ParseImpl(p.Queue); err != nil {
+			return nil, err
+		}
+	}
+	q := queue.NewWith[T](impl)
+	inputs := multiset.New[T]()
+	for _, item := range items {
+		q.Add(item)
+		inputs.Add(item)
+	}
+	if opts.shuffle {
+		shuffled, ok := q.(interface{ Shuffle(*rand.Rand) })
+		if !ok {
+			return nil, fmt.Errorf("pipeline %s: queue %s can not be shuffled", p.Name, impl)
+		}
+		shuffled.Shuffle(rand.New(rand.NewPCG(opts.seed, 0)))
+	}
+	if opts.item_timeout > 0 {
+		validator = validate.WithTimeout(validator, opts.item_timeout)
+	}
+	var memos *memo.Table[T, bool]
+	if opts.memo {
+		memos = memo.New[T, bool]()
+		validator = validate.Memoize(memos, validator)
+	}
+
+	// Start workers
+	pool_opts := []pool.Option[T]{pool.WithBuffer[T](p.Buffer), pool.WithNamePrefix[T](workerPrefix(p))}
+	if opts.stuck > 0 {
+		pool_opts = append(pool_opts, pool.WithStuckDetection[T](opts.stuck, true))
+	}
+	if opts.stats != nil {
+		pool_opts = append(pool_opts, pool.WithHistory[T](statsHistory))
+	}
+	if p.WAL != "" {
+		log, err := wal.Open(p.WAL, c)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline %s: %w", p.Name, err)
+		}
+		defer log.Close()
+		pool_opts = append(pool_opts, pool.WithWAL(log))
+	}
+	workers := manager.Add(opts.mgr, p.Name, p.Workers, validator, pool_opts...)
+	workers.Use(pool.Recover[T, bool]())
+	if opts.stats != nil {
+		opts.stats.add(p.Name, func() liveStats { return poolStats(p.Name, workers) })
+	}
+	if p.WAL != "" {
+		recovered, err := workers.Recover(p.WAL)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline %s: %w", p.Name, err)
+		}
+		if recovered > 0 {
+			fmt.Fprintf(os.Stderr, "pipeline %s: recovered %d unfinished items from %s\n", p.Name, recovered, p.WAL)
+		}
+	}
+
+	src := source.Generate(q.Next)
+	switch {
+	case opts.interactive:
+		src = stdinLines(p.Name, c, inputs, func(name string) error {
+			v, err := validate.Lookup[T](name)
+			if err != nil {
+				return err
+			}
+			workers.SetWorkFunc(v)
+			return nil
+		})
+	case opts.every > 0 && len(items) > 0:
+		// A continuous workload: the ticker cycles through the configured items
+		// until Ctrl-C, which ends the source like the last item of a batch,
+		// so the submitted items are still processed and reported.
+		// A second Ctrl-C kills the run.
+		ticks := make(chan T)
+		stopped := make(chan struct{})
+		sub := ticker.New(opts.every, opts.jitter, func(n int) T { return items[n%len(items)] }, func(item T) {
+			select {
+			case ticks <- item:
+			case <-stopped:
+			}
+		})
+		sub.Seed(opts.seed)
+		interrupted, stop_notify := signal.NotifyContext(context.Background(), os.Interrupt)
+		sub.Start()
+		joined := make(chan struct{})
+		go func() {
+			defer close(joined)
+			select {
+			case <-interrupted.Done():
+			case <-stopped:
+			}
+			stop_notify()
+			sub.Stop()
+			close(ticks) // no submit is running any more
+		}()
+		defer func() {
+			close(stopped)
+			<-joined
+		}()
+		src = source.Chan(ticks)
+	case opts.random > 0:
+		random := source.Random(rand.New(rand.NewPCG(opts.seed, 0)), opts.random, ty.random)
+		src = source.Func[T](func(ctx context.Context) (T, bool, error) {
+			item, ok, err := random.Next(ctx)
+			if ok {
+				inputs.Add(item)
+			}
+			return item, ok, err
+		})
+	case p.Input.Listen != "":
+		in, stop, err := listen(p.Name, p.Input.Listen, c, p.Buffer, workers)
+		if err != nil {
+			return nil, err
+		}
+		defer stop()
+		src = source.Func[T](func(ctx context.Context) (T, bool, error) {
+			item, ok, err := in.Next(ctx)
+			if ok {
+				inputs.Add(item)
+			}
+			return item, ok, err
+		})
+	}
+
+	total := reduce.Count[T, bool]()
+	valid := reduce.Count[T, bool]()
+
+	pipe := pipeline.New(workers)
+	is_valid := func(res pool.Result[T, bool]) bool { return res.Err == nil && res.Value }
+	var fed_back *reduce.Reducer[T, bool, int]
+	if opts.feedback > 0 && ty.normalize != nil {
+		pipe.Feedback(func(res pool.Result[T, bool]) (T, bool) {
+			if res.Value {
+				return res.Item, false
+			}
+			return ty.normalize(res.Item)
+		}, opts.feedback)
+		fed_back = reduce.Count[T, bool]()
+		pipe.Sink(reduce.Where(func(res pool.Result[T, bool]) bool { return res.Cycle > 0 }, fed_back))
+	}
+	for _, o := range p.Outputs {
+		var sink pipeline.Sink[T, bool]
+		switch o.Type {
+		case config.OutputConsole:
+			if opts.quiet {
+				continue
+			}
+			sink = pipeline.Writer(os.Stdout, func(res pool.Result[T, bool]) string {
+				worker := opts.paint.Worker(res.Worker, res.WorkerName)
+				if res.Err != nil {
+					return opts.msg.Sprintf("%v: item: %v error: %v", worker, res.Item, opts.paint.Error(res.Err))
+				}
+				return opts.msg.Sprintf("%v: item: %v result: %v", worker, res.Item, opts.paint.Result(res.Value, res.Value))
+			})
+		case config.OutputFile:
+			f, err := os.Create(o.Path)
+			if err != nil {
+				return nil, err
+			}
+			sink = pipeline.JSONLines(f, func(res pool.Result[T, bool]) any {
+				return resultLine[T]{ID: res.ID, Submitted: res.Submitted, Worker: res.Worker, Name: res.WorkerName, Item: res.Item, Valid: res.Value}
+			})
+		case config.OutputSSE:
+			stream := pipeline.NewSSE(func(res pool.Result[T, bool]) any {
+				return resultLine[T]{ID: res.ID, Submitted: res.Submitted, Worker: res.Worker, Name: res.WorkerName, Item: res.Item, Valid: res.Value}
+			})
+			stop, err := serve(p.Name, o.Addr, "/results", stream)
+			if err != nil {
+				return nil, err
+			}
+			defer stop()
+			sink = stream
+		}
+		if o.When != "" {
+			// Only the valid (or only the invalid) results take this branch
+			sink = pipeline.Route(is_valid, map[bool]pipeline.Sink[T, bool]{o.When == config.WhenValid: sink})
+		}
+		pipe.Sink(sink)
+	}
+	if opts.rec != nil {
+		pipe.Sink(trace.Sink[T, bool](opts.rec, p.Name))
+	}
+	if ty.join != nil {
+		pipe.Sink(ty.join)
+	}
+	if opts.check {
+		pipe.Sink(model.Sink[T, bool](p.Name))
+		defer model.Invariant(p.Name+": finished + pending == submitted", func() bool {
+			submitted, finished, pending := workers.Counts()
+			return finished+pending == submitted
+		})()
+		// counts are read first, both only grow
+		defer model.Invariant(p.Name+": counted results <= finished", func() bool {
+			counted := total.Value()
+			_, finished, _ := workers.Counts()
+			return counted <= finished
+		})()
+	}
+	pipe.Sink(total)
+	pipe.Sink(reduce.Where(is_valid, valid))
+	failed := reduce.Count[T, bool]()
+	pipe.Sink(reduce.Where(func(res pool.Result[T, bool]) bool { return res.Err != nil }, failed))
+	per_worker := reduce.GroupByKey(func(res pool.Result[T, bool]) string { return res.WorkerName })
+	pipe.Sink(per_worker)
+	var groups *collect.Groups[T, bool, string]
+	if ty.group != nil {
+		groups = collect.GroupBy[T, bool](ty.group)
+		pipe.Sink(reduce.Where(is_valid, groups))
+	}
+	var sampled *collect.Reservoir[T, bool]
+	if opts.sample > 0 {
+		sampled = collect.Sample[T, bool](opts.sample, opts.seed)
+		pipe.Sink(sampled)
+	}
+	var dups *collect.Dups[T, bool]
+	if opts.bloom > 0 {
+		filter, err := bloom.NewComparable[T](max(len(items), opts.random, listenItems), opts.bloom)
+		if err != nil {
+			return nil, err
+		}
+		dups = collect.Duplicates[T, bool](filter)
+		pipe.Sink(dups)
+	}
+	var greatest *collect.Top[T, bool]
+	if opts.top > 0 {
+		greatest = collect.TopK[T, bool](opts.top, ty.less)
+		pipe.Sink(reduce.Where(is_valid, greatest))
+	}
+	if opts.dot != nil {
+		if err := pipe.Dot(opts.dot); err != nil {
+			return nil, err
+		}
+	}
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+	run_err := pipe.From(ctx, src)
+	if run_err != nil && ctx.Err() == nil {
+		return nil, run_err
+	}
+	if err := workers.Err(); err != nil && p.WAL != "" {
+		return nil, fmt.Errorf("pipeline %s: %w", p.Name, err) // the log is incomplete
+	}
+	// An aborted run still reports its partial results
+	var text strings.Builder
+	opts.msg.Fprintf(&text, "Pipeline %s:\n", p.Name)
+	opts.msg.Fprintf(&text, "Number of valid items: %d\n", valid.Value())
+	if groups != nil {
+		by_key := groups.Groups()
+		for _, key := range slices.Sorted(maps.Keys(by_key)) {
+			fmt.Fprintf(&text, "  %s: %v\n", key, by_key[key])
+		}
+	}
+	if sampled != nil {
+		opts.msg.Fprintf(&text, "Sample of %d items: %v\n", sampled.Seen(), sampled.Items())
+	}
+	if dups != nil {
+		opts.msg.Fprintf(&text, "Probable duplicates (p=%g): %v\n", opts.bloom, dups.Flagged())
+	}
+	if memos != nil {
+		opts.msg.Fprintf(&text, "Validated distinct items: %d of %d\n", memos.Computed(), total.Value())
+	}
+	if fed_back != nil {
+		opts.msg.Fprintf(&text, "Fed back items: %d (at most %d cycles)\n", fed_back.Value(), opts.feedback)
+	}
+	if greatest != nil {
+		opts.msg.Fprintf(&text, "Greatest valid items: %v\n", greatest.Items())
+	}
+	if dups := inputs.TotalLen() - inputs.Len(); dups > 0 {
+		opts.msg.Fprintf(&text, "Duplicate inputs: %d\n", dups)
+		for item, n := range inputs.All() {
+			if n > 1 {
+				opts.msg.Fprintf(&text, "  %v: %d times\n", item, n)
+			}
+		}
+	}
+	if dead := workers.DeadLetters(); !dead.IsEmpty() {
+		opts.msg.Fprintf(&text, "Failed items: %v\n", dead)
+	}
+	lat := workers.Latency()
+	sum := summary{
+		Name:       p.Name,
+		Total:      total.Value(),
+		Valid:      valid.Value(),
+		Failed:     failed.Value(),
+		Workers:    per_worker.Value(),
+		Throughput: workers.Rate(),
+		Latency:    latency{P50: lat.Percentile(50), P95: lat.Percentile(95), P99: lat.Percentile(99)},
+	}
+	opts.msg.Fprintf(&text, "Throughput: %.1f items/s (last %v)\n", sum.Throughput, pool.RateWindow)
+	opts.msg.Fprintf(&text, "Latency: p50=%v p95=%v p99=%v\n", sum.Latency.P50, sum.Latency.P95, sum.Latency.P99)
+	return &report{summary: sum, text: text.String()}, run_err
+}
+
+/* In interactive mode every line typed on stdin becomes an item as soon
+ * as it is entered, and the console output echoes its result. A line that
+ * can not be decoded is reported and skipped, so a typo does not end the
+ * demo. The line ":validator <name>" swaps the validator of the running
+ * pool (see pool.Pool.SetWorkFunc): every item not processed yet, even
+ * one typed before but still waiting in the buffer, is checked by the new
+ * one. Reading stdin can not be interrupted, so the lines are read in a
+ * goroutine of their own and the source stops waiting for them once the
+ * run is aborted; the reader stays blocked until the next line or the end
+ * of the input, but no item is taken from it any more.
+ */
+
+// prefix of the interactive command replacing the validator
+const validatorCommand = ":validator "
+
+// source of the items typed on stdin, counted in inputs;
+// swap replaces the validator by the one registered under a name
+func stdinLines[T comparable](name string, c codec.Codec[T], inputs *multiset.Multiset[T], swap func(validator string) error) source.Source[T] {
+	lines := make(chan string)
+	var scan_err error // set before lines is closed
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		scan_err = scanner.Err()
+	}()
+	fmt.Fprintf(os.Stderr, "pipeline %s: type one item per line (or %q), end with Ctrl-D\n", name, validatorCommand+"<name>")
+	return source.Func[T](func(ctx context.Context) (T, bool, error) {
+		var zero T
+		for {
+			var line string
+			select {
+			case l, ok := <-lines:
+				if !ok {
+					return zero, false, scan_err
+				}
+				line = l
+			case <-ctx.Done():
+				return zero, false, ctx.Err()
+			}
+			if validator, ok := strings.CutPrefix(line, validatorCommand); ok {
+				if err := swap(strings.TrimSpace(validator)); err != nil {
+					fmt.Fprintf(os.Stderr, "pipeline %s: %v\n", name, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "pipeline %s: validator %s\n", name, strings.TrimSpace(validator))
+				}
+				continue
+			}
+			item, err := c.Decode([]byte(line))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "pipeline %s: %q: %v\n", name, line, err)
+				continue
+			}
+			inputs.Add(item)
+			return item, true, nil
+		}
+	})
+}
+
+// number of items the Bloom filter of an HTTP input is sized for
+const listenItems = 1024
+
+// pool that can be paused, see pool.Pool.Pause
+type pauser interface {
+	Pause() bool
+	Resume() bool
+	Paused() bool
+}
+
+// serve an HTTP source for pipeline name on addr until stop is called,
+// POST /pause and /resume pause and resume its workers
+func listen[T any](name, addr string, c codec.Codec[T], buffer int, workers pauser) (in *source.HTTP[T], stop func(), err error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pipeline %s: %w", name, err)
+	}
+	in = source.NewHTTP(c, buffer)
+	mux := http.NewServeMux()
+	mux.Handle("/", in.Handler())
+	mux.HandleFunc("POST /pause", func(w http.ResponseWriter, r *http.Request) {
+		pauseStatus(w, workers.Pause(), workers)
+	})
+	mux.HandleFunc("POST /resume", func(w http.ResponseWriter, r *http.Request) {
+		pauseStatus(w, workers.Resume(), workers)
+	})
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+	fmt.Fprintf(os.Stderr, "pipeline %s: accepting items on http://%s/submit until POST /close\n", name, l.Addr())
+	return in, func() {
+		srv.Close() // cancels the requests still waiting in a submit
+		in.Close()
+	}, nil
+}
+
+// answer a pause or resume request: 200 with the new state if it
+// changed, 409 if the pool already was in the requested state
+func pauseStatus(w http.ResponseWriter, changed bool, workers pauser) {
+	if !changed {
+		w.WriteHeader(http.StatusConflict)
+	}
+	fmt.Fprintf(w, "paused: %v\n", workers.Paused())
+}
+
+// time streams get to send their end event before the server is closed
+const shutdownGrace = time.Second
+
+// serve h as GET path on addr until stop is called
+func serve(name, addr, path string, h http.Handler) (stop func(), err error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline %s: %w", name, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET "+path, h)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+	fmt.Fprintf(os.Stderr, "pipeline %s: streaming results on http://%s%s\n", name, l.Addr(), path)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
+
+// decode the items of a configured input (none for an HTTP input)
+func loadItems[T any](in config.Input, c codec.Codec[T]) ([]T, error) {
+	var lines []string
+	switch {
+	case in.Range != nil:
+		for i := 0; i < in.Range.Count; i++ {
+			lines = append(lines, strconv.Itoa(in.Range.Start+i*in.Range.Step))
+		}
+	case in.File != "":
+		data, err := os.ReadFile(in.File)
+		if err != nil {
+			return nil, err
+		}
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	case in.Listen != "":
+		return nil, nil
+	default:
+		lines = in.Values
+	}
+
+	items := make([]T, 0, len(lines))
+	for _, line := range lines {
+		item, err := c.Decode([]byte(line))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+// words get a capital first letter, also if it is not ASCII
+func TestNormalizeString(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+		changed  bool
+	}{
+		{"  hello   world ", "Hello World", true},
+		{"über straße", "Über Straße", true},
+		{"Hello World", "Hello World", false},
+	} {
+		got, changed := normalizeString(tc.in)
+		if got != tc.want || changed != tc.changed {
+			t.Errorf("normalizeString(%q) = %q, %v, want %q, %v", tc.in, got, changed, tc.want, tc.changed)
+		}
+	}
+}
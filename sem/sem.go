@@ -0,0 +1,118 @@
+package sem
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+/* A weighted semaphore limits how much of a resource is used at once,
+ * independent of how many goroutines want to use it. Every caller
+ * acquires a weight (usually 1) and releases it when done.
+ * Waiters are served first in, first out, so a large request is not
+ * starved by a steady stream of small ones.
+ */
+
+// waiting Acquire call
+type waiter struct {
+	n     int64
+	ready chan struct{} // closed when the weight was granted
+}
+
+// weighted semaphore structure
+type Weighted struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+// create a new semaphore with a total weight of n
+func New(n int64) *Weighted {
+	return &Weighted{size: n}
+}
+
+// acquire weight n, blocks until it is available
+func (s *Weighted) Acquire(n int64) {
+	s.AcquireCtx(context.Background(), n)
+}
+
+// acquire weight n, blocks until it is available or ctx is done.
+// On failure nothing is acquired and ctx.Err() is returned.
+func (s *Weighted) AcquireCtx(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		// can never succeed, wait for ctx only
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	w := waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// granted in the meantime, give it back
+			s.cur -= n
+			s.notify()
+		default:
+			front := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			if front && s.size > s.cur {
+				s.notify()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// acquire weight n without blocking, reports whether it succeeded
+func (s *Weighted) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// release weight n
+func (s *Weighted) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("sem: released more than held")
+	}
+	s.notify()
+}
+
+// wake waiters in order as long as their weight fits
+func (s *Weighted) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,16 @@
+package skiplist
+
+import (
+	"cmp"
+	"math/rand/v2"
+)
+
+// create a new SkipList ordered by <
+func NewOrdered[K cmp.Ordered, V any]() *SkipList[K, V] {
+	return New[K, V](cmp.Less[K])
+}
+
+// create a new SkipList ordered by < drawing its levels from r
+func NewOrderedWithRand[K cmp.Ordered, V any](r *rand.Rand) *SkipList[K, V] {
+	return NewWithRand[K, V](cmp.Less[K], r)
+}
@@ -0,0 +1,169 @@
+package skiplist
+
+import (
+	"iter"
+	"math/rand/v2"
+)
+
+/* A skip list keeps its keys sorted in a linked list with additional
+ * express lanes: every node is linked on level 0 and, with probability
+ * 1/2 each, on the levels above. Searching starts on the highest level
+ * and moves down whenever the next key would be too large, so search,
+ * insertion and deletion take O(log n) on average without any
+ * rebalancing like a search tree needs. The levels are drawn from a
+ * random generator; NewWithRand takes a seeded one, so the structure
+ * and every run over it are reproducible.
+ */
+
+// highest number of levels, enough for 2^MaxLevel keys
+const MaxLevel = 32
+
+// node holding one key on its levels
+type node[K, V any] struct {
+	key   K
+	value V
+	next  []*node[K, V] // successor on every level of the node
+}
+
+// generic skip list structure mapping keys ordered by less to values
+type SkipList[K, V any] struct {
+	head  *node[K, V] // sentinel linked on all levels
+	level int         // levels in use
+	len   int
+	less  func(a, b K) bool
+	rand  *rand.Rand
+}
+
+// create a new SkipList ordered by less with randomly seeded levels
+func New[K, V any](less func(a, b K) bool) *SkipList[K, V] {
+	return NewWithRand[K, V](less, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
+
+// create a new SkipList ordered by less drawing its levels from r
+func NewWithRand[K, V any](less func(a, b K) bool, r *rand.Rand) *SkipList[K, V] {
+	return &SkipList[K, V]{
+		head:  &node[K, V]{next: make([]*node[K, V], MaxLevel)},
+		level: 1,
+		less:  less,
+		rand:  r,
+	}
+}
+
+// number of levels of a new node, each further level with probability 1/2
+func (s *SkipList[K, V]) randomLevel() int {
+	level := 1
+	for level < MaxLevel && s.rand.Uint64()&1 == 1 {
+		level++
+	}
+	return level
+}
+
+// first node with a key not less than key; if update is not nil,
+// it receives the last node before that key on every level
+func (s *SkipList[K, V]) find(key K, update *[MaxLevel]*node[K, V]) *node[K, V] {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && s.less(x.next[i].key, key) {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// reports whether n holds key
+func (s *SkipList[K, V]) holds(n *node[K, V], key K) bool {
+	return n != nil && !s.less(key, n.key)
+}
+
+// insert key with value, or replace the value if key is present;
+// returns true if key was inserted
+func (s *SkipList[K, V]) Set(key K, value V) bool {
+	var update [MaxLevel]*node[K, V]
+	if n := s.find(key, &update); s.holds(n, key) {
+		n.value = value
+		return false
+	}
+	level := s.randomLevel()
+	for i := s.level; i < level; i++ {
+		update[i] = s.head
+	}
+	s.level = max(s.level, level)
+	n := &node[K, V]{key: key, value: value, next: make([]*node[K, V], level)}
+	for i := range level {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	s.len++
+	return true
+}
+
+// value of key
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	if n := s.find(key, nil); s.holds(n, key) {
+		return n.value, true
+	}
+	var zero V
+	return zero, false // return default value and false if key is missing
+}
+
+// remove key, returns false if it was missing
+func (s *SkipList[K, V]) Delete(key K) bool {
+	var update [MaxLevel]*node[K, V]
+	n := s.find(key, &update)
+	if !s.holds(n, key) {
+		return false
+	}
+	for i := range n.next {
+		update[i].next[i] = n.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.len--
+	return true
+}
+
+// smallest key with its value
+func (s *SkipList[K, V]) Min() (K, V, bool) {
+	n := s.head.next[0]
+	if n == nil {
+		var key K
+		var value V
+		return key, value, false // return default values and false if list is empty
+	}
+	return n.key, n.value, true
+}
+
+// number of keys
+func (s *SkipList[K, V]) Len() int {
+	return s.len
+}
+
+// checks if the skip list is empty
+func (s *SkipList[K, V]) IsEmpty() bool {
+	return s.len == 0
+}
+
+// iterate over all keys and values in ascending order of the keys
+func (s *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return s.iterate(s.head.next[0], func(K) bool { return true })
+}
+
+// iterate in ascending order over the keys from (inclusive) to (exclusive)
+func (s *SkipList[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return s.iterate(s.find(from, nil), func(key K) bool { return s.less(key, to) })
+}
+
+// iterate on level 0 from n as long as in accepts the keys
+func (s *SkipList[K, V]) iterate(first *node[K, V], in func(K) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := first; n != nil && in(n.key); n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package skiplist
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// fixed seed of the levels and the operations
+const seed = 42
+
+// seeded skip list of ints
+func newSeeded() *SkipList[int, int] {
+	return NewOrderedWithRand[int, int](rand.New(rand.NewPCG(seed, 0)))
+}
+
+// every level is sorted and every node reachable on a level is linked on level 0
+func checkLevels(t *testing.T, s *SkipList[int, int]) {
+	t.Helper()
+	on_base := make(map[*node[int, int]]bool)
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		on_base[n] = true
+	}
+	for level := range s.level {
+		for n := s.head.next[level]; n != nil; n = n.next[level] {
+			if !on_base[n] {
+				t.Fatalf("level %d: key %d is not linked on level 0", level, n.key)
+			}
+			if next := n.next[level]; next != nil && !s.less(n.key, next.key) {
+				t.Fatalf("level %d: key %d before %d", level, n.key, next.key)
+			}
+		}
+	}
+}
+
+// random Set, Delete and Get calls agree with a map
+func TestAgainstMap(t *testing.T) {
+	s := newSeeded()
+	want := make(map[int]int)
+	r := rand.New(rand.NewPCG(seed, 1))
+	for i := range 5000 {
+		key := r.IntN(500)
+		switch r.IntN(3) {
+		case 0:
+			_, present := want[key]
+			if inserted := s.Set(key, i); inserted == present {
+				t.Fatalf("Set(%d) = %v with key present: %v", key, inserted, present)
+			}
+			want[key] = i
+		case 1:
+			_, present := want[key]
+			if deleted := s.Delete(key); deleted != present {
+				t.Fatalf("Delete(%d) = %v with key present: %v", key, deleted, present)
+			}
+			delete(want, key)
+		default:
+			value, ok := s.Get(key)
+			if w, present := want[key]; ok != present || value != w {
+				t.Fatalf("Get(%d) = %d, %v, want %d, %v", key, value, ok, w, present)
+			}
+		}
+		if s.Len() != len(want) {
+			t.Fatalf("Len = %d, want %d", s.Len(), len(want))
+		}
+	}
+	checkLevels(t, s)
+
+	keys := slices.Sorted(maps.Keys(want))
+	var got []int
+	for k, v := range s.All() {
+		if v != want[k] {
+			t.Fatalf("value of %d = %d, want %d", k, v, want[k])
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, keys) {
+		t.Fatalf("All = %v, want %v", got, keys)
+	}
+	if k, _, ok := s.Min(); !ok || k != keys[0] {
+		t.Fatalf("Min = %d, %v, want %d", k, ok, keys[0])
+	}
+}
+
+// Range yields the keys in [from, to) in ascending order
+func TestRange(t *testing.T) {
+	s := newSeeded()
+	for _, k := range []int{9, 1, 7, 3, 5} {
+		s.Set(k, k*10)
+	}
+	var got []int
+	for k, v := range s.Range(3, 9) {
+		if v != k*10 {
+			t.Fatalf("value of %d = %d", k, v)
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, []int{3, 5, 7}) {
+		t.Fatalf("Range(3, 9) = %v, want [3 5 7]", got)
+	}
+}
+
+// the same seed builds the same levels
+func TestSeedReproducible(t *testing.T) {
+	a, b := newSeeded(), newSeeded()
+	for k := range 200 {
+		a.Set(k, k)
+		b.Set(k, k)
+	}
+	for na, nb := a.head.next[0], b.head.next[0]; na != nil; na, nb = na.next[0], nb.next[0] {
+		if len(na.next) != len(nb.next) {
+			t.Fatalf("key %d: %d levels and %d levels", na.key, len(na.next), len(nb.next))
+		}
+	}
+	if a.level != b.level {
+		t.Fatalf("levels in use %d and %d", a.level, b.level)
+	}
+}
@@ -0,0 +1,114 @@
+package source
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+)
+
+/* The HTTP source lets other programs feed a running pipeline:
+ *   POST /submit  body decoded with the codec, answered with
+ *                 202 Accepted once the pipeline took the item
+ *   POST /close   no more items, the source is exhausted
+ *                 after the accepted ones
+ * A submit blocks until the pipeline takes the item (or the request is
+ * cancelled), so a slow pipeline slows its clients down instead of
+ * buffering without bound. Close does not wait for blocked submits: it
+ * closes the done channel, which they select on as well, so they answer
+ * 410 Gone even if the pipeline stopped reading. Next only reports false
+ * after the submits in progress have returned and the accepted items are
+ * taken, so every item answered with 202 is delivered.
+ */
+
+// maximum size of a submitted body
+const MaxBody = 1 << 20
+
+// source of items posted over HTTP
+type HTTP[T any] struct {
+	c       codec.Codec[T]
+	items   chan T
+	done    chan struct{} // closed by Close
+	mu      sync.RWMutex  // guards closed and adding to submits
+	closed  bool
+	submits sync.WaitGroup // submits in progress
+}
+
+// create a new HTTP source buffering up to buffer items
+func NewHTTP[T any](c codec.Codec[T], buffer int) *HTTP[T] {
+	return &HTTP[T]{c: c, items: make(chan T, buffer), done: make(chan struct{})}
+}
+
+// handler serving POST /submit and POST /close
+func (h *HTTP[T]) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /submit", h.submit)
+	mux.HandleFunc("POST /close", func(w http.ResponseWriter, r *http.Request) {
+		h.Close()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// decode the body and hand the item to the pipeline
+func (h *HTTP[T]) submit(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item, err := h.c.Decode(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		http.Error(w, "source closed", http.StatusGone)
+		return
+	}
+	h.submits.Add(1)
+	h.mu.RUnlock()
+	defer h.submits.Done()
+	select {
+	case h.items <- item:
+		w.WriteHeader(http.StatusAccepted)
+	case <-h.done:
+		http.Error(w, "source closed", http.StatusGone)
+	case <-r.Context().Done():
+	}
+}
+
+// next submitted item, false once closed and all accepted items are taken
+func (h *HTTP[T]) Next(ctx context.Context) (T, bool, error) {
+	var zero T
+	select {
+	case item := <-h.items:
+		return item, true, nil
+	case <-h.done:
+	case <-ctx.Done():
+		return zero, false, ctx.Err()
+	}
+	// closed: the remaining submits return at once, then items is final
+	h.submits.Wait()
+	select {
+	case item := <-h.items:
+		return item, true, nil
+	default:
+		return zero, false, nil
+	}
+}
+
+// accept no more items; safe to call more than once
+func (h *HTTP[T]) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+	return nil
+}
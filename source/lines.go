@@ -0,0 +1,57 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+)
+
+// source decoding one item per line
+type Lines[T any] struct {
+	scanner *bufio.Scanner
+	closer  io.Closer // nil if the reader is not ours to close
+	c       codec.Codec[T]
+	line    int
+}
+
+// create a new Lines source reading from r
+func NewLines[T any](r io.Reader, c codec.Codec[T]) *Lines[T] {
+	return &Lines[T]{scanner: bufio.NewScanner(r), c: c}
+}
+
+// open the file at path as a Lines source, closed by Close
+func File[T any](path string, c codec.Codec[T]) (*Lines[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	l := NewLines(f, c)
+	l.closer = f
+	return l, nil
+}
+
+// decode the next line, errors name the line number
+func (l *Lines[T]) Next(context.Context) (T, bool, error) {
+	var zero T
+	if !l.scanner.Scan() {
+		return zero, false, l.scanner.Err()
+	}
+	l.line++
+	item, err := l.c.Decode(l.scanner.Bytes())
+	if err != nil {
+		return zero, false, fmt.Errorf("source: line %d: %w", l.line, err)
+	}
+	return item, true, nil
+}
+
+// close the file opened by File
+func (l *Lines[T]) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
@@ -0,0 +1,74 @@
+package source
+
+import (
+	"context"
+	"math/rand/v2"
+)
+
+/* A source delivers the items of a pipeline one after another. Unlike
+ * a plain next function it gets a context, so a blocking source (a
+ * channel, a network connection) can give up when the pipeline is
+ * aborted, and it can fail, e.g. on an undecodable line of a file.
+ * Every source reports false once it is exhausted and keeps doing so.
+ */
+
+// supplier of items
+type Source[T any] interface {
+	Next(ctx context.Context) (T, bool, error)
+}
+
+// adapter to use a function as a source
+type Func[T any] func(ctx context.Context) (T, bool, error)
+
+func (f Func[T]) Next(ctx context.Context) (T, bool, error) {
+	return f(ctx)
+}
+
+// source of the items of a slice, in order
+func Slice[T any](items []T) Source[T] {
+	i := 0
+	return Func[T](func(context.Context) (T, bool, error) {
+		if i == len(items) {
+			var zero T
+			return zero, false, nil // return default value and false if all items were delivered
+		}
+		i++
+		return items[i-1], true, nil
+	})
+}
+
+// source of the items received from ch until it is closed;
+// fails with the context's error while waiting after ctx is done
+func Chan[T any](ch <-chan T) Source[T] {
+	return Func[T](func(ctx context.Context) (T, bool, error) {
+		var zero T
+		select {
+		case item, ok := <-ch:
+			return item, ok, nil
+		case <-ctx.Done():
+			return zero, false, ctx.Err()
+		}
+	})
+}
+
+// source of the items returned by next until it reports false
+// (e.g. queue.Next or an iterator pulled with iter.Pull)
+func Generate[T any](next func() (T, bool)) Source[T] {
+	return Func[T](func(context.Context) (T, bool, error) {
+		item, ok := next()
+		return item, ok, nil
+	})
+}
+
+// source of n items created by gen from r, so a fixed seed
+// reproduces the same items
+func Random[T any](r *rand.Rand, n int, gen func(r *rand.Rand) T) Source[T] {
+	return Func[T](func(context.Context) (T, bool, error) {
+		if n == 0 {
+			var zero T
+			return zero, false, nil // return default value and false after n items
+		}
+		n--
+		return gen(r), true, nil
+	})
+}
@@ -0,0 +1,162 @@
+package stack
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+/* A blocking stack lets consumers wait for items with a condition
+ * variable instead of a channel, which could only offer FIFO order.
+ * It wraps any Stacker, so the underlying implementation stays
+ * exchangeable. Waiting always happens in a loop that re-checks the
+ * stack after every wake-up: a wake-up only means the state may have
+ * changed (another consumer may have popped the item first, or the
+ * wake-up is spurious), never that an item is guaranteed to be there.
+ *
+ * Close ends the stack for producers and, once it is drained, for consumers:
+ * it broadcasts to all waiting consumers, which then return the remaining
+ * items and afterwards ErrClosed, so a consumer looping on WaitPop stops
+ * cleanly. Because Push can fail after Close, Blocking is not a Stacker.
+ */
+
+// generic blocking stack structure, safe for concurrent use
+type Blocking[T any] struct {
+	mu     sync.Mutex
+	ready  *sync.Cond // signalled after every Push
+	s      Stacker[T]
+	closed bool
+}
+
+// create a new blocking stack around s, a slice stack if s is nil
+func NewBlocking[T any](s Stacker[T]) *Blocking[T] {
+	if s == nil {
+		s = New[T]()
+	}
+	b := &Blocking[T]{s: s}
+	b.ready = sync.NewCond(&b.mu)
+	return b
+}
+
+// add item to the top of stack and wake up one waiting consumer,
+// fails with ErrClosed after Close
+func (b *Blocking[T]) Push(item T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	b.s.Push(item)
+	b.ready.Signal()
+	return nil
+}
+
+// remove and return from top of the stack without waiting
+func (b *Blocking[T]) Pop() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.s.Pop()
+}
+
+// remove and return from top of the stack, blocks until an item
+// is available; returns ErrClosed once the stack is closed and empty
+func (b *Blocking[T]) WaitPop() (T, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.s.IsEmpty() && !b.closed {
+		b.ready.Wait()
+	}
+	if b.s.IsEmpty() {
+		var zero T
+		return zero, false, ErrClosed // return default value and false if stack is closed
+	}
+	item, _ := b.s.Pop()
+	return item, true, nil
+}
+
+// remove and return from top of the stack without waiting; fails with
+// ErrEmpty if there is no item, or ErrClosed if the stack is closed as well
+func (b *Blocking[T]) TryPop() (T, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if item, ok := b.s.Pop(); ok {
+		return item, nil
+	}
+	var zero T
+	if b.closed {
+		return zero, ErrClosed
+	}
+	return zero, ErrEmpty
+}
+
+// like WaitPop, but gives up with ErrTimeout or ErrCancelled once ctx ends
+func (b *Blocking[T]) WaitPopContext(ctx context.Context) (T, error) {
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.ready.Broadcast()
+	})
+	defer stop()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.s.IsEmpty() && !b.closed && ctx.Err() == nil {
+		b.ready.Wait()
+	}
+	if item, ok := b.s.Pop(); ok {
+		return item, nil
+	}
+	var zero T
+	if b.closed {
+		return zero, ErrClosed
+	}
+	return zero, ctxErr(ctx)
+}
+
+// reject further items and release all waiting consumers
+func (b *Blocking[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.ready.Broadcast()
+}
+
+// return from top of the stack
+func (b *Blocking[T]) Peek() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.s.Peek()
+}
+
+// checks if the stack is empty
+func (b *Blocking[T]) IsEmpty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.s.IsEmpty()
+}
+
+/* Snapshot copies the stack while holding the lock, so observers like a
+ * stats endpoint see a consistent state even while producers and
+ * consumers keep working. Stacker has no way to look at all items, so
+ * they are popped and pushed back in the original order.
+ */
+
+// copy of all items from bottom to top
+func (b *Blocking[T]) Snapshot() []T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var items []T
+	for item, ok := b.s.Pop(); ok; item, ok = b.s.Pop() {
+		items = append(items, item)
+	}
+	slices.Reverse(items)
+	for _, item := range items {
+		b.s.Push(item)
+	}
+	return items
+}
+
+// iterate over a snapshot of the stack from bottom to top
+func (b *Blocking[T]) All() iter.Seq[T] {
+	return slices.Values(b.Snapshot())
+}
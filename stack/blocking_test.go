@@ -0,0 +1,216 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/internal/lincheck"
+)
+
+// time given to goroutines to block in a wait
+const settle = 20 * time.Millisecond
+
+// a waiter woken while the stack is empty has to wait again
+func TestBlockingWakeUpWhileEmpty(t *testing.T) {
+	b := NewBlocking[int](nil)
+	got := make(chan int)
+	go func() {
+		item, _, _ := b.WaitPop()
+		got <- item
+	}()
+	time.Sleep(settle)
+
+	// spurious wake-up
+	b.mu.Lock()
+	b.ready.Broadcast()
+	b.mu.Unlock()
+	select {
+	case item := <-got:
+		t.Fatalf("WaitPop returned %d from an empty stack", item)
+	case <-time.After(settle):
+	}
+
+	b.Push(7)
+	if item := <-got; item != 7 {
+		t.Fatalf("WaitPop = %d, want 7", item)
+	}
+}
+
+// an item popped by another consumer first sends the woken waiter back to waiting
+func TestBlockingItemTakenFirst(t *testing.T) {
+	b := NewBlocking[int](nil)
+	got := make(chan int)
+	go func() {
+		item, _, _ := b.WaitPop()
+		got <- item
+	}()
+	time.Sleep(settle)
+
+	// push and pop the item before the waiter can run
+	b.mu.Lock()
+	b.s.Push(1)
+	b.ready.Signal()
+	b.s.Pop()
+	b.mu.Unlock()
+	select {
+	case item := <-got:
+		t.Fatalf("WaitPop returned %d, the item was already taken", item)
+	case <-time.After(settle):
+	}
+
+	b.Push(2)
+	if item := <-got; item != 2 {
+		t.Fatalf("WaitPop = %d, want 2", item)
+	}
+}
+
+// all concurrent waiters are released with ErrClosed by Close
+func TestBlockingCloseReleasesWaiters(t *testing.T) {
+	b := NewBlocking[int](nil)
+	const waiters = 8
+	errs := make(chan error, waiters)
+	var wg sync.WaitGroup
+	for range waiters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := b.WaitPop()
+			if ok {
+				t.Error("WaitPop took an item from an empty stack")
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(settle)
+	b.Close()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("WaitPop = %v, want ErrClosed", err)
+		}
+	}
+}
+
+// a closed stack rejects new items but hands out the remaining ones first
+func TestBlockingCloseDrains(t *testing.T) {
+	b := NewBlocking[int](nil)
+	b.Push(1)
+	b.Push(2)
+	b.Close()
+	if err := b.Push(3); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Push after Close = %v, want ErrClosed", err)
+	}
+	for _, want := range []int{2, 1} {
+		item, ok, err := b.WaitPop()
+		if !ok || err != nil || item != want {
+			t.Fatalf("WaitPop = %d, %v, %v, want %d", item, ok, err, want)
+		}
+	}
+	if _, _, err := b.WaitPop(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("WaitPop on a drained stack = %v, want ErrClosed", err)
+	}
+	if _, err := b.TryPop(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("TryPop on a drained stack = %v, want ErrClosed", err)
+	}
+	if _, err := b.WaitPopContext(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("WaitPopContext on a drained stack = %v, want ErrClosed", err)
+	}
+}
+
+// an open, empty stack fails with ErrEmpty without waiting and with ErrTimeout once the context ends
+func TestBlockingEmpty(t *testing.T) {
+	b := NewBlocking[int](nil)
+	if _, err := b.TryPop(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("TryPop = %v, want ErrEmpty", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), settle)
+	defer cancel()
+	_, err := b.WaitPopContext(ctx)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitPopContext = %v, want ErrTimeout", err)
+	}
+}
+
+// a snapshot lists the items from bottom to top and leaves the stack unchanged
+func TestBlockingSnapshot(t *testing.T) {
+	b := NewBlocking[int](nil)
+	for i := range 5 {
+		b.Push(i)
+	}
+	b.Pop()
+	if got := b.Snapshot(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("Snapshot = %v, want [0 1 2 3]", got)
+	}
+	if got := slices.Collect(b.All()); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("All = %v, want [0 1 2 3]", got)
+	}
+	if item, _ := b.Pop(); item != 3 {
+		t.Fatalf("Pop after Snapshot = %d, want 3", item)
+	}
+}
+
+// snapshots taken while producers and consumers keep working are always
+// increasing from bottom to top, as every producer pushes increasing items
+func TestBlockingSnapshotConcurrent(t *testing.T) {
+	b := NewBlocking[int](nil)
+	const n = 10000
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range n {
+			b.Push(i)
+		}
+		b.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			if _, ok, _ := b.WaitPop(); !ok {
+				return
+			}
+		}
+	}()
+	for range 200 {
+		items := b.Snapshot()
+		if !slices.IsSorted(items) {
+			t.Fatalf("inconsistent snapshot %v", items)
+		}
+	}
+	wg.Wait()
+}
+
+// histories of concurrent push and pop calls are linearizable
+func TestBlockingLinearizable(t *testing.T) {
+	const goroutines, ops = 4, 8
+	for round := range 50 {
+		b := NewBlocking[int](nil)
+		var h lincheck.History[int]
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range ops {
+					if (g+i)%2 == 0 {
+						item := g*ops + i
+						h.Do("push", item, func() (int, bool) {
+							return 0, b.Push(item) == nil
+						})
+						continue
+					}
+					h.Do("pop", 0, b.Pop)
+				}
+			}()
+		}
+		wg.Wait()
+		if !lincheck.Check(lincheck.StackModel[int](), h.Ops()) {
+			t.Fatalf("round %d: history is not linearizable: %+v", round, h.Ops())
+		}
+	}
+}
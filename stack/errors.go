@@ -0,0 +1,30 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/* Operations that can fail return one of these sentinel errors, so
+ * callers branch with errors.Is instead of interpreting a bool.
+ * A wait ended by its context fails with ErrTimeout or ErrCancelled
+ * wrapping the context's error as well, so both
+ * errors.Is(err, stack.ErrTimeout) and errors.Is(err, context.DeadlineExceeded) hold.
+ */
+
+var (
+	ErrEmpty     = errors.New("stack: empty")     // no item to take
+	ErrClosed    = errors.New("stack: closed")    // the stack was closed
+	ErrTimeout   = errors.New("stack: timeout")   // the deadline of the context passed
+	ErrCancelled = errors.New("stack: cancelled") // the context was cancelled
+)
+
+// error for a wait ended by ctx
+func ctxErr(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrCancelled, err)
+}
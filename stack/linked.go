@@ -0,0 +1,76 @@
+package stack
+
+import "github.com/juli-99/hka-modell_basierte_software/arena"
+
+/* The linked stack allocates one node per element instead of growing
+ * a slice. Push never copies existing elements, but every element costs
+ * an allocation and a pointer, and the nodes are scattered in memory.
+ */
+
+// single element of a linked stack
+type node[T any] struct {
+	item T
+	next *node[T]
+}
+
+// generic linked-node stack structure
+type Linked[T any] struct {
+	head  *node[T]
+	arena *arena.Arena[node[T]] // nil allocates every node on its own
+}
+
+// create a new Linked stack
+func NewLinked[T any]() *Linked[T] {
+	return &Linked[T]{}
+}
+
+// create a new Linked stack allocating its nodes in blocks of size (see arena)
+func NewLinkedArena[T any](size int) *Linked[T] {
+	return &Linked[T]{arena: arena.New[node[T]](size)}
+}
+
+// add item to the top of stack
+func (s *Linked[T]) Push(item T) {
+	if s.arena == nil {
+		s.head = &node[T]{item: item, next: s.head}
+		return
+	}
+	n := s.arena.Alloc()
+	n.item, n.next = item, s.head
+	s.head = n
+}
+
+// remove and return from top of the stack
+func (s *Linked[T]) Pop() (T, bool) {
+	if s.head == nil {
+		var default_val T
+		return default_val, false // return default value and false if stack is empty
+	}
+	old := s.head
+	s.head = old.next
+	item := old.item
+	*old = node[T]{} // an arena block keeps the node alive, it must not keep the item and the rest of the stack
+	return item, true
+}
+
+// return from top of the stack
+func (s *Linked[T]) Peek() (T, bool) {
+	if s.head == nil {
+		var zero T
+		return zero, false // return default value and false if stack is empty
+	}
+	return s.head.item, true
+}
+
+// checks if the stack is empty
+func (s *Linked[T]) IsEmpty() bool {
+	return s.head == nil
+}
+
+// remove all elements at once, dropping the arena blocks with them
+func (s *Linked[T]) Free() {
+	s.head = nil
+	if s.arena != nil {
+		s.arena.Free()
+	}
+}
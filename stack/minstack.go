@@ -0,0 +1,56 @@
+package stack
+
+/* The min-stack stores next to every element the minimum of all elements
+ * up to and including it. The minimum of the whole stack is therefore
+ * always stored on top and Min is O(1), also after Pop, at the cost of
+ * one extra value per element.
+ */
+
+// element together with the minimum below and including it
+type minEntry[T any] struct {
+	item T
+	min  T
+}
+
+// generic stack structure with O(1) minimum
+type MinStack[T any] struct {
+	stack Stack[minEntry[T]]
+	less  func(a, b T) bool
+}
+
+// create a new MinStack ordered by less
+func NewMinStack[T any](less func(a, b T) bool) *MinStack[T] {
+	return &MinStack[T]{less: less}
+}
+
+// add item to the top of stack
+func (s *MinStack[T]) Push(item T) {
+	lowest := item
+	if top, ok := s.stack.Peek(); ok && s.less(top.min, item) {
+		lowest = top.min
+	}
+	s.stack.Push(minEntry[T]{item: item, min: lowest})
+}
+
+// remove and return from top of the stack
+func (s *MinStack[T]) Pop() (T, bool) {
+	e, ok := s.stack.Pop()
+	return e.item, ok
+}
+
+// return from top of the stack
+func (s *MinStack[T]) Peek() (T, bool) {
+	e, ok := s.stack.Peek()
+	return e.item, ok
+}
+
+// return the smallest element on the stack
+func (s *MinStack[T]) Min() (T, bool) {
+	e, ok := s.stack.Peek()
+	return e.min, ok // default value and false if stack is empty
+}
+
+// checks if the stack is empty
+func (s *MinStack[T]) IsEmpty() bool {
+	return s.stack.IsEmpty()
+}
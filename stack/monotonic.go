@@ -0,0 +1,56 @@
+package stack
+
+/* A monotonic stack keeps its elements ordered: before an item is pushed,
+ * every element on top for which evict(top, item) holds is popped.
+ * With evict = "top < item" the stack stays decreasing from bottom to top
+ * and each evicted element has just met its next greater element, which
+ * solves next-greater-element style problems in O(n) overall, as every
+ * element is pushed and popped at most once.
+ */
+
+// generic monotonic stack structure
+type Monotonic[T any] struct {
+	stack Stack[T]
+	evict func(top, item T) bool
+}
+
+// create a new Monotonic stack popping top before pushing item while evict(top, item)
+func NewMonotonic[T any](evict func(top, item T) bool) *Monotonic[T] {
+	return &Monotonic[T]{evict: evict}
+}
+
+// pop all elements evicted by item, push item and return the evicted
+// elements in the order they were popped (top first)
+func (s *Monotonic[T]) Push(item T) []T {
+	var evicted []T
+	for {
+		top, ok := s.stack.Peek()
+		if !ok || !s.evict(top, item) {
+			break
+		}
+		s.stack.Pop()
+		evicted = append(evicted, top)
+	}
+	s.stack.Push(item)
+	return evicted
+}
+
+// remove and return from top of the stack
+func (s *Monotonic[T]) Pop() (T, bool) {
+	return s.stack.Pop()
+}
+
+// return from top of the stack
+func (s *Monotonic[T]) Peek() (T, bool) {
+	return s.stack.Peek()
+}
+
+// checks if the stack is empty
+func (s *Monotonic[T]) IsEmpty() bool {
+	return s.stack.IsEmpty()
+}
+
+// number of elements on the stack
+func (s *Monotonic[T]) Len() int {
+	return len(s.stack.items)
+}
@@ -0,0 +1,28 @@
+package stack
+
+import "cmp"
+
+/* For element types with a natural order (numbers, strings) passing a
+ * comparison function is only noise, so these constructors use cmp.Less.
+ * They are thin wrappers: for any other T, or a different order,
+ * the comparator-based constructors are used directly.
+ */
+
+// create a new MinStack ordered by <
+func NewOrderedMinStack[T cmp.Ordered]() *MinStack[T] {
+	return NewMinStack(cmp.Less[T])
+}
+
+// create a new Monotonic stack decreasing from bottom to top,
+// an item evicts all smaller elements (next greater element)
+func NewDecreasing[T cmp.Ordered]() *Monotonic[T] {
+	return NewMonotonic(cmp.Less[T])
+}
+
+// create a new Monotonic stack increasing from bottom to top,
+// an item evicts all greater elements (next smaller element)
+func NewIncreasing[T cmp.Ordered]() *Monotonic[T] {
+	return NewMonotonic(func(top, item T) bool {
+		return cmp.Less(item, top)
+	})
+}
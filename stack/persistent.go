@@ -0,0 +1,89 @@
+package stack
+
+/* A persistent stack is immutable: Push and Pop return a new version and
+ * leave the old one untouched. All versions share their common tail,
+ * so keeping an old version (e.g. as a snapshot) costs O(1).
+ * PersistentStack wraps the current version to satisfy Stacker.
+ */
+
+// immutable stack node
+type pnode[T any] struct {
+	item T
+	next *pnode[T]
+}
+
+// generic immutable stack value, the zero value is the empty stack
+type Persistent[T any] struct {
+	head *pnode[T]
+	size int
+}
+
+// return a new version with item on top
+func (s Persistent[T]) Push(item T) Persistent[T] {
+	return Persistent[T]{head: &pnode[T]{item: item, next: s.head}, size: s.size + 1}
+}
+
+// return the top item and the version without it
+func (s Persistent[T]) Pop() (T, Persistent[T], bool) {
+	if s.head == nil {
+		var default_val T
+		return default_val, s, false // return default value and false if stack is empty
+	}
+	return s.head.item, Persistent[T]{head: s.head.next, size: s.size - 1}, true
+}
+
+// return from top of the stack
+func (s Persistent[T]) Peek() (T, bool) {
+	if s.head == nil {
+		var zero T
+		return zero, false // return default value and false if stack is empty
+	}
+	return s.head.item, true
+}
+
+// number of items
+func (s Persistent[T]) Len() int {
+	return s.size
+}
+
+// checks if the stack is empty
+func (s Persistent[T]) IsEmpty() bool {
+	return s.head == nil
+}
+
+// mutable handle to the current version of a persistent stack
+type PersistentStack[T any] struct {
+	cur Persistent[T]
+}
+
+// create a new PersistentStack
+func NewPersistent[T any]() *PersistentStack[T] {
+	return &PersistentStack[T]{}
+}
+
+// add item to the top of stack
+func (s *PersistentStack[T]) Push(item T) {
+	s.cur = s.cur.Push(item)
+}
+
+// remove and return from top of the stack
+func (s *PersistentStack[T]) Pop() (T, bool) {
+	item, next, ok := s.cur.Pop()
+	s.cur = next
+	return item, ok
+}
+
+// return from top of the stack
+func (s *PersistentStack[T]) Peek() (T, bool) {
+	return s.cur.Peek()
+}
+
+// checks if the stack is empty
+func (s *PersistentStack[T]) IsEmpty() bool {
+	return s.cur.IsEmpty()
+}
+
+// current version, unaffected by later changes
+func (s *PersistentStack[T]) Snapshot() Persistent[T] {
+	return s.cur
+}
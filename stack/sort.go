@@ -0,0 +1,66 @@
+package stack
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+)
+
+/* Sort orders the stack so that Pop returns the items in ascending order
+ * of less: the smallest item ends up on top. It is stable in pop order,
+ * equal items are popped in the same order as before. The slice holds the
+ * items bottom to top, so it is reversed before and after a stable sort.
+ */
+
+// sort the stack in place so that Pop returns the smallest item first
+func (s *Stack[T]) Sort(less func(a, b T) bool) {
+	slices.Reverse(s.items)
+	slices.SortStableFunc(s.items, compare(less))
+	slices.Reverse(s.items)
+}
+
+// reports whether Pop returns the items in ascending order of less
+func (s *Stack[T]) IsSorted(less func(a, b T) bool) bool {
+	for i := len(s.items) - 1; i > 0; i-- {
+		if less(s.items[i-1], s.items[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/* SearchFunc relies on the order established by Sort, positions are
+ * counted in pop order (0 is the top). If the stack is not sorted by less
+ * the index is still in [0, len], but meaningless; a reported presence is
+ * always right, a missing one may be wrong.
+ */
+
+// position of target counted from the top of a stack sorted by less
+// (or where it would be inserted), and whether it is present
+func (s *Stack[T]) SearchFunc(target T, less func(a, b T) bool) (int, bool) {
+	n := len(s.items)
+	i := sort.Search(n, func(i int) bool {
+		return !less(s.items[n-1-i], target)
+	})
+	return i, i < n && !less(target, s.items[n-1-i])
+}
+
+// put the items in a random order drawn from r, the same seed gives the same order
+func (s *Stack[T]) Shuffle(r *rand.Rand) {
+	r.Shuffle(len(s.items), func(i, j int) {
+		s.items[i], s.items[j] = s.items[j], s.items[i]
+	})
+}
+
+// three-way comparison for the slices package built from less
+func compare[T any](less func(a, b T) bool) func(a, b T) int {
+	return func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	}
+}
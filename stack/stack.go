@@ -1,5 +1,13 @@
 package stack
 
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juli-99/hka-modell_basierte_software/growth"
+)
+
 /* Using generics for the stack makes sense
  * if we want it to be type-safe and consistent.
  * If we needed a stack that could store different types of values,
@@ -9,9 +17,26 @@ package stack
  * avoiding the need for type assertions and reducing the risk of runtime errors.
  */
 
+// interface implemented by all stacks of this package
+type Stacker[T any] interface {
+	Push(item T)
+	Pop() (T, bool)
+	Peek() (T, bool)
+	IsEmpty() bool
+}
+
+// all implementations can be used interchangeably
+var (
+	_ Stacker[int] = (*Stack[int])(nil)
+	_ Stacker[int] = (*Linked[int])(nil)
+	_ Stacker[int] = (*PersistentStack[int])(nil)
+	_ Stacker[int] = (*MinStack[int])(nil)
+)
+
 // generic stack structure
 type Stack[T any] struct {
-	items []T
+	items  []T
+	growth growth.Policy
 }
 
 // create a new Stack
@@ -19,9 +44,14 @@ func New[T any]() *Stack[T] {
 	return &Stack[T]{}
 }
 
+// create a new Stack growing according to policy
+func NewWithGrowth[T any](policy growth.Policy) *Stack[T] {
+	return &Stack[T]{growth: policy}
+}
+
 // add item to the top of stack
 func (s *Stack[T]) Push(item T) {
-	s.items = append(s.items, item)
+	s.items = growth.Push(s.growth, s.items, item)
 }
 
 // remove and return from top of the stack
@@ -48,3 +78,59 @@ func (s *Stack[T]) Peek() (T, bool) {
 func (s *Stack[T]) IsEmpty() bool {
 	return len(s.items) == 0
 }
+
+// number of elements shown by String before eliding the middle
+const maxShown = 8
+
+// short description like "Stack[int] len=21 top=145 [5 12 19 ... 131 138 145]"
+func (s *Stack[T]) String() string {
+	var zero T
+	var b strings.Builder
+	fmt.Fprintf(&b, "Stack[%T] len=%d", zero, len(s.items))
+	if len(s.items) > 0 {
+		fmt.Fprintf(&b, " top=%v", s.items[len(s.items)-1])
+	}
+	if len(s.items) > maxShown {
+		fmt.Fprintf(&b, " [%s ... %s]", format(s.items[:maxShown/2], " ", nil), format(s.items[len(s.items)-maxShown/2:], " ", nil))
+	} else {
+		fmt.Fprintf(&b, " [%s]", s.Format(" ", nil))
+	}
+	return b.String()
+}
+
+// all elements from bottom to top converted by f (fmt.Sprint if nil), joined by sep
+func (s *Stack[T]) Format(sep string, f func(T) string) string {
+	return format(s.items, sep, f)
+}
+
+// items converted by f (fmt.Sprint if nil), joined by sep
+func format[T any](items []T, sep string, f func(T) string) string {
+	if f == nil {
+		f = func(item T) string { return fmt.Sprint(item) }
+	}
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(f(item))
+	}
+	return b.String()
+}
+
+// write all elements to w, one per line
+func (s *Stack[T]) Dump(w io.Writer) error {
+	var zero T
+	if _, err := fmt.Fprintf(w, "Stack[%T] len=%d\n", zero, len(s.items)); err != nil {
+		return err
+	}
+	if len(s.items) == 0 {
+		return nil
+	}
+	i := 0
+	_, err := fmt.Fprintln(w, s.Format("\n", func(item T) string {
+		i++
+		return fmt.Sprintf("%d: %v", i-1, item)
+	}))
+	return err
+}
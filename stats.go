@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* run -stats (or serve) answers GET /stats with the live figures of every
+ * pipeline as JSON while the pipelines are running, so a long run can be
+ * watched without waiting for the final summary. The pools have different
+ * element types, so every pipeline registers a function that takes its
+ * figures instead of the pool itself. The last results come from the
+ * history of the pool (pool.WithHistory), kept in a ring buffer.
+ */
+
+// address served by the serve command without -stats
+const defaultStatsAddr = ":8080"
+
+// number of last results of every pipeline listed by -stats
+const statsHistory = 20
+
+// run the pipelines and serve their stats, takes all flags of run
+func serveCmd(args []string) error {
+	// a later -stats in args overrides the default
+	return runCmd(append([]string{"-stats", defaultStatsAddr}, args...))
+}
+
+// live figures of a pipeline
+type liveStats struct {
+	Name       string  `json:"name"`
+	Submitted  int     `json:"submitted"`
+	Finished   int     `json:"finished"`
+	Pending    int     `json:"pending"`
+	Throughput float64 `json:"throughput"` // items/s over the last pool.RateWindow
+	Latency    latency `json:"latency"`
+	Recent     any     `json:"recent"` // last results as []resultLine, oldest first
+}
+
+// figures of the running pipelines by name, safe for concurrent use
+type statsServer struct {
+	mu        sync.Mutex
+	pipelines map[string]func() liveStats
+}
+
+// create a new stats server without pipelines
+func newStatsServer() *statsServer {
+	return &statsServer{pipelines: make(map[string]func() liveStats)}
+}
+
+// serve the figures of the named pipeline taken by stats
+func (s *statsServer) add(name string, stats func() liveStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pipelines[name] = stats
+}
+
+// answer with the figures of all pipelines ordered by name
+func (s *statsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	names := slices.Sorted(maps.Keys(s.pipelines))
+	out := struct {
+		Pipelines []liveStats `json:"pipelines"`
+	}{Pipelines: make([]liveStats, 0, len(names))}
+	for _, name := range names {
+		out.Pipelines = append(out.Pipelines, s.pipelines[name]())
+	}
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// serve s on addr, the returned function stops the server
+func (s *statsServer) listen(addr string) (func() error, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /stats", s)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+	fmt.Fprintf(os.Stderr, "stats: serving on http://%s/stats\n", l.Addr())
+	return srv.Close, nil
+}
+
+// current figures of the pipeline name processed by p
+func poolStats[T any](name string, p *pool.Pool[T, bool]) liveStats {
+	submitted, finished, pending := p.Counts()
+	lat := p.Latency()
+	recent := p.Recent()
+	lines := make([]resultLine[T], len(recent))
+	for i, res := range recent {
+		lines[i] = resultLine[T]{ID: res.ID, Submitted: res.Submitted, Worker: res.Worker, Name: res.WorkerName, Item: res.Item, Valid: res.Value}
+	}
+	return liveStats{
+		Name:       name,
+		Submitted:  submitted,
+		Finished:   finished,
+		Pending:    pending,
+		Throughput: p.Rate(),
+		Latency:    latency{P50: lat.Percentile(50), P95: lat.Percentile(95), P99: lat.Percentile(99)},
+		Recent:     lines,
+	}
+}
@@ -0,0 +1,92 @@
+package ticker
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+/* A periodic submitter generates an item every interval and hands it to
+ * a submit function (e.g. a pool's Submit), simulating a continuous
+ * workload instead of a fixed batch. A jitter randomizes every interval
+ * by up to ±jitter, so workers do not always see perfectly even load.
+ */
+
+// generic periodic submitter structure
+type Submitter[T any] struct {
+	every  time.Duration
+	jitter time.Duration
+	gen    func(n int) T
+	submit func(T)
+	rng    *rand.Rand
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// create a new Submitter calling submit(gen(n)) every interval, n counting from 0
+func New[T any](every, jitter time.Duration, gen func(n int) T, submit func(T)) *Submitter[T] {
+	return &Submitter[T]{
+		every:  every,
+		jitter: jitter,
+		gen:    gen,
+		submit: submit,
+		rng:    rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+	}
+}
+
+// use a fixed seed for the jitter, must be called before Start
+func (s *Submitter[T]) Seed(seed uint64) {
+	s.rng = rand.New(rand.NewPCG(seed, 0))
+}
+
+// start submitting in the background, does nothing if already running
+func (s *Submitter[T]) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// stop submitting and wait until the background goroutine has finished
+func (s *Submitter[T]) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// submit loop
+func (s *Submitter[T]) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	timer := time.NewTimer(s.next())
+	defer timer.Stop()
+	for n := 0; ; n++ {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+		s.submit(s.gen(n))
+		timer.Reset(s.next())
+	}
+}
+
+// duration until the next submit
+func (s *Submitter[T]) next() time.Duration {
+	d := s.every
+	if s.jitter > 0 {
+		d += time.Duration(s.rng.Int64N(int64(2*s.jitter)+1)) - s.jitter
+	}
+	return max(d, 0)
+}
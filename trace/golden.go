@@ -0,0 +1,89 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+/* Golden traces catch behavioral changes of the pipeline: a trace of a
+ * known run is stored in a file and every later run has to produce the
+ * same trace. A live run is not deterministic, so traces are normalized
+ * before comparing: the timing and the worker that happened to process
+ * an item are dropped, and the events are ordered by pipeline and item id.
+ * What is left (which item got which result or error) has to be stable.
+ */
+
+// error returned when a trace differs from its golden file
+var ErrMismatch = errors.New("trace: differs from golden file")
+
+// read all events of a trace
+func Read(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("trace: line %d: %w", line, err)
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// copy of events without timing and workers, sorted by pipeline and id
+func Normalize(events []Event) []Event {
+	norm := make([]Event, len(events))
+	for i, e := range events {
+		e.At, e.Worker, e.Name = 0, 0, ""
+		norm[i] = e
+	}
+	slices.SortStableFunc(norm, func(a, b Event) int {
+		return cmp.Or(cmp.Compare(a.Pipeline, b.Pipeline), cmp.Compare(a.ID, b.ID))
+	})
+	return norm
+}
+
+// compare the normalized events with the golden file at path,
+// with update the file is (re)written instead
+func Golden(path string, events []Event, update bool) error {
+	var want bytes.Buffer
+	enc := json.NewEncoder(&want)
+	for _, e := range Normalize(events) {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if update {
+		return os.WriteFile(path, want.Bytes(), 0o644)
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	got_lines := bytes.Split(want.Bytes(), []byte("\n"))
+	golden_lines := bytes.Split(golden, []byte("\n"))
+	for i := range max(len(got_lines), len(golden_lines)) {
+		var got, exp []byte
+		if i < len(got_lines) {
+			got = got_lines[i]
+		}
+		if i < len(golden_lines) {
+			exp = golden_lines[i]
+		}
+		if !bytes.Equal(got, exp) {
+			return fmt.Errorf("%w %s at line %d:\n  got:  %s\n  want: %s", ErrMismatch, path, i+1, got, exp)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package trace
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+/* The Mermaid export turns a trace into a sequence diagram for
+ * write-ups: for every item the producer hands it to the worker that
+ * processed it, and the worker hands the result to the collector.
+ * Only the first items (by id) of every pipeline are drawn, larger
+ * diagrams are not readable anyway. The trace only records results,
+ * so both arrows of an item appear at the time of its result.
+ */
+
+// write a Mermaid sequence diagram of the first m items of every pipeline
+func Mermaid(w io.Writer, events []Event, m int) error {
+	var shown []Event
+	for _, e := range events {
+		if e.ID <= uint64(m) {
+			shown = append(shown, e)
+		}
+	}
+	slices.SortStableFunc(shown, func(a, b Event) int {
+		return cmp.Compare(a.At, b.At)
+	})
+
+	// participants in a stable order: producer, workers, collector
+	var workers []string
+	for _, e := range shown {
+		if w := worker(e); !slices.Contains(workers, w) {
+			workers = append(workers, w)
+		}
+	}
+	slices.Sort(workers)
+
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n")
+	b.WriteString("    participant P as Producer\n")
+	for _, w := range workers {
+		fmt.Fprintf(&b, "    participant %s\n", w)
+	}
+	b.WriteString("    participant C as Collector\n")
+	for _, e := range shown {
+		fmt.Fprintf(&b, "    P->>%s: #%d %s\n", worker(e), e.ID, e.Item)
+		if e.Err != "" {
+			fmt.Fprintf(&b, "    %s--x C: #%d error\n", worker(e), e.ID)
+		} else {
+			fmt.Fprintf(&b, "    %s->>C: #%d %s\n", worker(e), e.ID, e.Value)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// participant name of the worker of e
+func worker(e Event) string {
+	if e.Pipeline == "" {
+		return fmt.Sprintf("W%d", e.Worker)
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, e.Pipeline)
+	return fmt.Sprintf("%s_W%d", name, e.Worker)
+}
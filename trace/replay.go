@@ -0,0 +1,39 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/bus"
+)
+
+/* Replay publishes the events of a recorded trace on a bus as if the
+ * pipeline was running right now, so every subscriber written for live
+ * runs (console output, metrics, a visualization) works offline as well.
+ * speed scales the time between events: 1 is the original timing,
+ * 2 twice as fast, and 0 (or less) publishes without waiting at all.
+ */
+
+// publish the trace read from r on b at the given speed
+func Replay(r io.Reader, speed float64, b *bus.Bus[Event]) error {
+	start := time.Now()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("trace: line %d: %w", line, err)
+		}
+		if speed > 0 {
+			due := start.Add(time.Duration(float64(e.At) / speed))
+			time.Sleep(time.Until(due))
+		}
+		b.Publish(e)
+	}
+	return scanner.Err()
+}
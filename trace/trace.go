@@ -0,0 +1,91 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/pipeline"
+	"github.com/juli-99/hka-modell_basierte_software/pool"
+)
+
+/* A trace is the recorded sequence of events of one or more pipeline
+ * runs, written as one JSON object per line. Items, values and errors
+ * are stored formatted as text, so pipelines of different types end up
+ * in the same trace and a trace can be read without knowing their types.
+ * Every event carries its offset to the start of the recording, which
+ * is what Replay needs to reproduce the original timing.
+ */
+
+// kinds of events
+const (
+	KindResult = "result" // item processed
+	KindFailed = "failed" // item processed with an error
+)
+
+// single recorded event
+type Event struct {
+	At       time.Duration `json:"at"` // since the start of the recording
+	Kind     string        `json:"kind"`
+	Pipeline string        `json:"pipeline,omitempty"`
+	Worker   int           `json:"worker"`
+	Name     string        `json:"worker_name,omitempty"` // of the worker, see pool.WithNamePrefix
+	ID       uint64        `json:"id"`
+	Item     string        `json:"item"`
+	Value    string        `json:"value,omitempty"`
+	Err      string        `json:"err,omitempty"`
+}
+
+// event describing res of the named pipeline (At is left zero)
+func FromResult[T, R any](name string, res pool.Result[T, R]) Event {
+	e := Event{
+		Kind:     KindResult,
+		Pipeline: name,
+		Worker:   res.Worker,
+		Name:     res.WorkerName,
+		ID:       res.ID,
+		Item:     fmt.Sprint(res.Item),
+		Value:    fmt.Sprint(res.Value),
+	}
+	if res.Err != nil {
+		e.Kind, e.Err = KindFailed, res.Err.Error()
+	}
+	return e
+}
+
+// name of the worker of e, "worker <id>" in traces recorded without names
+func (e Event) WorkerName() string {
+	if e.Name == "" {
+		return fmt.Sprintf("worker %d", e.Worker)
+	}
+	return e.Name
+}
+
+// structure writing a trace, safe for concurrent use
+type Recorder struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	start time.Time
+}
+
+// create a new Recorder writing to w, offsets are measured from now
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w), start: time.Now()}
+}
+
+// write e with its offset set to the time since the start of the recording
+func (r *Recorder) Record(e Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.At = time.Since(r.start)
+	return r.enc.Encode(e)
+}
+
+// sink recording every result of the named pipeline
+func Sink[T, R any](r *Recorder, name string) pipeline.Sink[T, R] {
+	return pipeline.SinkFunc[T, R](func(res pool.Result[T, R]) error {
+		return r.Record(FromResult(name, res))
+	})
+}
@@ -0,0 +1,117 @@
+package unionfind
+
+/* A union-find structure (disjoint set forest) partitions its elements
+ * into sets: every set is a tree whose root represents it. Union links
+ * the root of the lower tree below the root of the higher one (union by
+ * rank, the rank bounds the height), and Find points every element it
+ * passes directly at the root (path compression). Together both make
+ * a sequence of operations run in nearly constant amortized time.
+ * Elements are numbered on Add, so the forest itself is kept in slices
+ * and only the lookup of an element goes through a map.
+ */
+
+// generic union-find structure
+type UnionFind[T comparable] struct {
+	index  map[T]int
+	items  []T
+	parent []int
+	rank   []uint8
+	sets   int
+}
+
+// create a new UnionFind
+func New[T comparable]() *UnionFind[T] {
+	return &UnionFind[T]{index: make(map[T]int)}
+}
+
+// add item as a set of its own, returns false if it was already added
+func (u *UnionFind[T]) Add(item T) bool {
+	if _, ok := u.index[item]; ok {
+		return false
+	}
+	u.index[item] = len(u.items)
+	u.items = append(u.items, item)
+	u.parent = append(u.parent, len(u.parent))
+	u.rank = append(u.rank, 0)
+	u.sets++
+	return true
+}
+
+// representative of the set of item
+func (u *UnionFind[T]) Find(item T) (T, bool) {
+	i, ok := u.index[item]
+	if !ok {
+		var zero T
+		return zero, false // return default value and false if item was not added
+	}
+	return u.items[u.root(i)], true
+}
+
+// root of element i, compressing the path on the way
+func (u *UnionFind[T]) root(i int) int {
+	root := i
+	for u.parent[root] != root {
+		root = u.parent[root]
+	}
+	for u.parent[i] != root {
+		u.parent[i], i = root, u.parent[i]
+	}
+	return root
+}
+
+// merge the sets of a and b, adding them if necessary;
+// returns false if they already were in the same set
+func (u *UnionFind[T]) Union(a, b T) bool {
+	u.Add(a)
+	u.Add(b)
+	ra, rb := u.root(u.index[a]), u.root(u.index[b])
+	if ra == rb {
+		return false
+	}
+	if u.rank[ra] < u.rank[rb] {
+		ra, rb = rb, ra
+	}
+	u.parent[rb] = ra
+	if u.rank[ra] == u.rank[rb] {
+		u.rank[ra]++
+	}
+	u.sets--
+	return true
+}
+
+// reports whether a and b were added and are in the same set
+func (u *UnionFind[T]) Connected(a, b T) bool {
+	ia, ok := u.index[a]
+	if !ok {
+		return false
+	}
+	ib, ok := u.index[b]
+	return ok && u.root(ia) == u.root(ib)
+}
+
+// number of elements
+func (u *UnionFind[T]) Len() int {
+	return len(u.items)
+}
+
+// number of disjoint sets
+func (u *UnionFind[T]) Sets() int {
+	return u.sets
+}
+
+// all sets, each with its elements in the order they were added
+func (u *UnionFind[T]) Groups() [][]T {
+	at := make(map[int]int) // root -> position in groups
+	var groups [][]T
+	for i, item := range u.items {
+		root := u.root(i)
+		g, ok := at[root]
+		if !ok {
+			g = len(groups)
+			at[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], item)
+	}
+	return groups
+}
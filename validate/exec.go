@@ -0,0 +1,71 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+	"github.com/juli-99/hka-modell_basierte_software/sem"
+)
+
+/* Exec plugs validation logic written in any language into a pipeline:
+ * every item is encoded with the codec and written, followed by a
+ * newline, to the stdin of a new process; exit status 0 means valid, any
+ * other status invalid. The command's stderr goes to ours. Starting a
+ * process per item is expensive, so at most procs processes run at the
+ * same time (see Limit), and a process still running after timeout is
+ * killed and its item counted as invalid. Arguments are passed as they
+ * are, without a shell.
+ * Failing to encode the item or to run the command says nothing about
+ * the item, so the validator panics with the error instead of returning
+ * a verdict; pool.Recover turns that into a failed Result.
+ * ExecContext also kills the process once the context of the validation
+ * is done, e.g. because AnyContext already has its verdict.
+ */
+
+// time Exec waits for the stdin of a killed command to be released
+const killDelay = time.Second
+
+// validator running the command name with args for every item
+func Exec[T any](c codec.Codec[T], procs int, timeout time.Duration, name string, args ...string) (Validator[T], error) {
+	v, err := ExecContext(c, procs, timeout, name, args...)
+	if err != nil {
+		return nil, err
+	}
+	return v.Validator(), nil
+}
+
+// like Exec, but kills the process once ctx is done
+func ExecContext[T any](c codec.Codec[T], procs int, timeout time.Duration, name string, args ...string) (ContextValidator[T], error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("validate: exec: %w", err)
+	}
+	run := func(ctx context.Context, item T) bool {
+		data, err := c.Encode(item)
+		if err != nil {
+			panic(fmt.Errorf("validate: exec %s: %w", name, err))
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, path, args...)
+		cmd.Stdin = bytes.NewReader(append(data, '\n'))
+		cmd.Stderr = os.Stderr
+		cmd.WaitDelay = killDelay
+		err = cmd.Run()
+		var exit *exec.ExitError
+		switch {
+		case err == nil:
+			return true
+		case ctx.Err() != nil, errors.As(err, &exit):
+			return false
+		}
+		panic(fmt.Errorf("validate: exec %s: %w", name, err))
+	}
+	return LimitContext(sem.New(int64(procs)), run), nil
+}
@@ -0,0 +1,173 @@
+package validate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/juli-99/hka-modell_basierte_software/memo"
+	"github.com/juli-99/hka-modell_basierte_software/sem"
+)
+
+/* Small library of reusable validators. The numeric ones are generic
+ * over all integer (or ordered) types, so the same code validates
+ * int, int64 or uint8 pipelines alike.
+ */
+
+// integer types the numeric validators work on
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// checks if n is a prime number
+func IsPrime[T Integer](n T) bool {
+	if n < 2 {
+		return false
+	}
+	for d := T(2); d <= n/d; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// checks if n is a composite number (neither prime nor below 4)
+func IsComposite[T Integer](n T) bool {
+	return n > 3 && !IsPrime(n)
+}
+
+// validator accepting values between lo and hi (both inclusive)
+func InRange[T cmp.Ordered](lo, hi T) Validator[T] {
+	return func(v T) bool {
+		return v >= lo && v <= hi
+	}
+}
+
+// validator accepting multiples of n
+func DivisibleBy[T Integer](n T) Validator[T] {
+	return func(v T) bool {
+		return n != 0 && v%n == 0
+	}
+}
+
+// validator accepting strings matching the regular expression
+func MatchesRegex(pattern string) (Validator[string], error) {
+	re, err := NewRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.Validate, nil
+}
+
+// validator accepting strings with at most n characters (runes)
+func MaxLength(n int) Validator[string] {
+	return func(s string) bool {
+		return utf8.RuneCountInString(s) <= n
+	}
+}
+
+// checks if s reads the same backwards (compared rune by rune)
+func IsPalindrome(s string) bool {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		if runes[i] != runes[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// register the library validators, names taking arguments are
+// "range:<min>,<max>", "divisible:<n>", "regex:<pattern>" and "maxlen:<n>"
+func init() {
+	Register("composite", IsComposite[int])
+	Register("palindrome", IsPalindrome)
+	Register("range", Factory[int](func(arg string) (Validator[int], error) {
+		lo_str, hi_str, ok := strings.Cut(arg, ",")
+		if !ok {
+			return nil, fmt.Errorf("validate: range needs <min>,<max>, got %q", arg)
+		}
+		lo, err := strconv.Atoi(strings.TrimSpace(lo_str))
+		if err != nil {
+			return nil, err
+		}
+		hi, err := strconv.Atoi(strings.TrimSpace(hi_str))
+		if err != nil {
+			return nil, err
+		}
+		return InRange(lo, hi), nil
+	}))
+	Register("divisible", Factory[int](func(arg string) (Validator[int], error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("validate: divisible by zero")
+		}
+		return DivisibleBy(n), nil
+	}))
+	Register("regex", Factory[string](MatchesRegex))
+	Register("maxlen", Factory[string](func(arg string) (Validator[string], error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		return MaxLength(n), nil
+	}))
+}
+
+// validator running v while holding weight 1 of s,
+// caps how many items are validated by v at the same time
+func Limit[T any](s *sem.Weighted, v Validator[T]) Validator[T] {
+	return LimitContext(s, IgnoreContext(v)).Validator()
+}
+
+// like Limit, but stops waiting for s once ctx is done
+func LimitContext[T any](s *sem.Weighted, v ContextValidator[T]) ContextValidator[T] {
+	return func(ctx context.Context, item T) bool {
+		if s.AcquireCtx(ctx, 1) != nil {
+			return false
+		}
+		defer s.Release(1)
+		return v(ctx, item)
+	}
+}
+
+// validator remembering the verdict of v for every item in table,
+// every distinct item is validated by v only once, even if several
+// workers validate it at the same time
+func Memoize[T comparable](table *memo.Table[T, bool], v Validator[T]) Validator[T] {
+	return func(item T) bool {
+		return table.GetOrCompute(item, v)
+	}
+}
+
+/* WithTimeout can not stop a validator that overran, Go has no way to
+ * kill a goroutine: it keeps running in the background until it returns
+ * and its verdict is dropped. The worker, however, is free again after d.
+ * A panic of v is passed on to the caller, so pool.Recover still sees it.
+ */
+
+// validator running v in its own goroutine, an item whose validation
+// takes longer than d counts as invalid
+func WithTimeout[T any](v Validator[T], d time.Duration) Validator[T] {
+	return func(item T) bool {
+		done := make(chan verdict, 1) // the goroutine never blocks on an abandoned item
+		go validateAsync(context.Background(), IgnoreContext(v), item, done)
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case res := <-done:
+			return res.valid()
+		case <-timer.C:
+			return false
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package validate
+
+import "context"
+
+/* Any and AllParallel run their validators concurrently, one goroutine
+ * per validator and item, and return as soon as the verdict is certain:
+ * Any at the first validator accepting the item, AllParallel at the
+ * first one rejecting it. The context of the remaining validators is
+ * cancelled then, so AnyContext and AllParallelContext stop e.g. the
+ * processes of ExecContext and free their slots. Plain validators can not
+ * be stopped and are abandoned, like in WithTimeout; their verdicts go to
+ * a buffered channel nobody reads. This pays off when the validators are
+ * slow and independent, for fast ones the goroutines cost more than they
+ * save.
+ */
+
+// verdict of a validator running in its own goroutine
+type verdict struct {
+	ok    bool
+	panic any // recovered panic of the validator, nil if it returned
+}
+
+// verdict of the validator, panics again if the validator panicked
+func (v verdict) valid() bool {
+	if v.panic != nil {
+		panic(v.panic)
+	}
+	return v.ok
+}
+
+// send the verdict of v for item to done, also if v panics
+func validateAsync[T any](ctx context.Context, v ContextValidator[T], item T, done chan<- verdict) {
+	defer func() {
+		if r := recover(); r != nil {
+			done <- verdict{panic: r}
+		}
+	}()
+	done <- verdict{ok: v(ctx, item)}
+}
+
+// run all validators concurrently, returns decisive as soon as one of
+// them returns it, otherwise !decisive once all have returned;
+// the validators still running are cancelled on return
+func race[T any](ctx context.Context, vs []ContextValidator[T], item T, decisive bool) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan verdict, len(vs))
+	for _, v := range vs {
+		go validateAsync(ctx, v, item, done)
+	}
+	for range vs {
+		if (<-done).valid() == decisive {
+			return decisive
+		}
+	}
+	return !decisive
+}
+
+// vs as context validators that can not be cancelled
+func ignoreContext[T any](vs []Validator[T]) []ContextValidator[T] {
+	cvs := make([]ContextValidator[T], len(vs))
+	for i, v := range vs {
+		cvs[i] = IgnoreContext(v)
+	}
+	return cvs
+}
+
+// validator accepting an item if at least one of vs accepts it,
+// the validators run concurrently
+func Any[T any](vs ...Validator[T]) Validator[T] {
+	return AnyContext(ignoreContext(vs)...).Validator()
+}
+
+// validator accepting an item if all of vs accept it,
+// the validators run concurrently
+func AllParallel[T any](vs ...Validator[T]) Validator[T] {
+	return AllParallelContext(ignoreContext(vs)...).Validator()
+}
+
+// like Any, but cancels the validators still running once one accepts the item
+func AnyContext[T any](vs ...ContextValidator[T]) ContextValidator[T] {
+	return func(ctx context.Context, item T) bool {
+		return race(ctx, vs, item, true)
+	}
+}
+
+// like AllParallel, but cancels the validators still running once one rejects the item
+func AllParallelContext[T any](vs ...ContextValidator[T]) ContextValidator[T] {
+	return func(ctx context.Context, item T) bool {
+		return race(ctx, vs, item, false)
+	}
+}
@@ -0,0 +1,145 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+/* Regex compiles its pattern once and validates strings by matching
+ * them. Capture goes one step further for pipelines that need the parts
+ * of a match downstream: it fills the fields of a struct S with the named
+ * groups of the pattern, converted to the field types, so the results of
+ * the pool carry typed values instead of strings to parse again.
+ * A field takes the group with its name, or the one named by its tag
+ * `regex:"name"`. Fields and groups are matched once when Capture is
+ * called, a field naming a missing group is an error then, not a surprise
+ * on the first item.
+ */
+
+// compiled regular expression validating strings
+type Regex struct {
+	re *regexp.Regexp
+}
+
+// compile pattern once for validating and capturing
+func NewRegex(pattern string) (*Regex, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Regex{re: re}, nil
+}
+
+// reports whether s matches, usable as Validator[string]
+func (r *Regex) Validate(s string) bool {
+	return r.re.MatchString(s)
+}
+
+// named groups of s, nil if s does not match
+func (r *Regex) Captures(s string) map[string]string {
+	m := r.re.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	captures := make(map[string]string)
+	for i, name := range r.re.SubexpNames() {
+		if name != "" {
+			captures[name] = m[i]
+		}
+	}
+	return captures
+}
+
+// verdict of a Regex with the captures of a match
+type Match[S any] struct {
+	Valid    bool // the string matched and all captures could be converted
+	Captures S    // zero unless Valid
+}
+
+// field of S filled from a group of the pattern
+type captureField struct {
+	field int // index in S
+	group int // index of the submatch
+	set   func(v reflect.Value, s string) error
+}
+
+// work function matching a string and capturing its named groups into S,
+// a struct with fields of kind string, bool, int, uint or float
+func Capture[S any](r *Regex) (func(string) Match[S], error) {
+	t := reflect.TypeFor[S]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: captures need a struct, got %v", t)
+	}
+	var fields []captureField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("regex"); ok {
+			name = tag
+		}
+		group := r.re.SubexpIndex(name)
+		if group < 0 {
+			return nil, fmt.Errorf("validate: field %s: no group %q in %s", f.Name, name, r.re)
+		}
+		set, err := setter(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("validate: field %s: %w", f.Name, err)
+		}
+		fields = append(fields, captureField{field: i, group: group, set: set})
+	}
+	return func(s string) Match[S] {
+		m := r.re.FindStringSubmatch(s)
+		if m == nil {
+			return Match[S]{}
+		}
+		var captures S
+		v := reflect.ValueOf(&captures).Elem()
+		for _, f := range fields {
+			if err := f.set(v.Field(f.field), m[f.group]); err != nil {
+				return Match[S]{}
+			}
+		}
+		return Match[S]{Valid: true, Captures: captures}
+	}, nil
+}
+
+// function converting a capture to a value of type t
+func setter(t reflect.Type) (func(v reflect.Value, s string) error, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value, s string) error {
+			v.SetString(s)
+			return nil
+		}, nil
+	case reflect.Bool:
+		return func(v reflect.Value, s string) error {
+			b, err := strconv.ParseBool(s)
+			v.SetBool(b)
+			return err
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value, s string) error {
+			n, err := strconv.ParseInt(s, 10, t.Bits())
+			v.SetInt(n)
+			return err
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value, s string) error {
+			n, err := strconv.ParseUint(s, 10, t.Bits())
+			v.SetUint(n)
+			return err
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value, s string) error {
+			f, err := strconv.ParseFloat(s, t.Bits())
+			v.SetFloat(f)
+			return err
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
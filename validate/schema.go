@@ -0,0 +1,172 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/* A schema validates structs by the rules declared in their field tags,
+ * so items decoded from JSON are checked without writing a validator
+ * per type:
+ *
+ *	type person struct {
+ *		Name string `validate:"required,max=40,pattern=^[A-Z]"`
+ *		Age  int    `validate:"min=0,max=150"`
+ *	}
+ *
+ * required rejects the zero value; min and max bound numbers, and the
+ * length of strings, slices and maps; pattern matches strings. As a
+ * pattern may contain commas, it has to be the last rule of a tag.
+ * The tags are parsed, the bounds converted and the patterns compiled
+ * once in NewSchema, so a typo in a tag is an error there and checking
+ * an item only compares values.
+ */
+
+// rule checked against one field, returns nil if the value satisfies it
+type rule func(v reflect.Value) error
+
+// rules of one field
+type fieldRules struct {
+	index int
+	name  string
+	rules []rule
+}
+
+// rules of all fields of the struct T
+type Schema[T any] struct {
+	fields []fieldRules
+}
+
+// build the schema of T from its `validate` tags
+func NewSchema[T any]() (*Schema[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: schema needs a struct, got %v", t)
+	}
+	s := &Schema[T]{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("validate")
+		if !ok || tag == "" {
+			continue
+		}
+		rules, err := parseRules(f.Type, tag)
+		if err != nil {
+			return nil, fmt.Errorf("validate: field %s: %w", f.Name, err)
+		}
+		s.fields = append(s.fields, fieldRules{index: i, name: f.Name, rules: rules})
+	}
+	return s, nil
+}
+
+// rules of a tag for a field of type t
+func parseRules(t reflect.Type, tag string) ([]rule, error) {
+	var rules []rule
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "pattern=") {
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+		name, arg, _ := strings.Cut(part, "=")
+		var r rule
+		var err error
+		switch name {
+		case "required":
+			r = func(v reflect.Value) error {
+				if v.IsZero() {
+					return errors.New("is required")
+				}
+				return nil
+			}
+		case "min", "max":
+			r, err = bound(t, name == "min", arg)
+		case "pattern":
+			r, err = pattern(t, arg)
+		default:
+			err = fmt.Errorf("unknown rule %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// rule bounding a number, or the length of a string, slice or map
+func bound(t reflect.Type, lower bool, arg string) (rule, error) {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bound %q: %w", arg, err)
+	}
+	var measure func(v reflect.Value) float64
+	what := "value"
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		measure = func(v reflect.Value) float64 { return float64(v.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		measure = func(v reflect.Value) float64 { return float64(v.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		measure = func(v reflect.Value) float64 { return v.Float() }
+	case reflect.String:
+		measure = func(v reflect.Value) float64 { return float64(len([]rune(v.String()))) }
+		what = "length"
+	case reflect.Slice, reflect.Map:
+		measure = func(v reflect.Value) float64 { return float64(v.Len()) }
+		what = "length"
+	default:
+		return nil, fmt.Errorf("no bounds for %v", t)
+	}
+	return func(v reflect.Value) error {
+		switch m := measure(v); {
+		case lower && m < limit:
+			return fmt.Errorf("%s %v is less than %v", what, m, limit)
+		case !lower && m > limit:
+			return fmt.Errorf("%s %v is greater than %v", what, m, limit)
+		}
+		return nil
+	}, nil
+}
+
+// rule matching a string against a regular expression
+func pattern(t reflect.Type, expr string) (rule, error) {
+	if t.Kind() != reflect.String {
+		return nil, fmt.Errorf("no pattern for %v", t)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) error {
+		if !re.MatchString(v.String()) {
+			return fmt.Errorf("%q does not match %s", v.String(), re)
+		}
+		return nil
+	}, nil
+}
+
+// first violated rule of every field of item, nil if it is valid
+func (s *Schema[T]) Check(item T) error {
+	v := reflect.ValueOf(item)
+	var errs []error
+	for _, f := range s.fields {
+		for _, r := range f.rules {
+			if err := r(v.Field(f.index)); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reports whether item satisfies all rules, usable as Validator[T]
+func (s *Schema[T]) Validate(item T) bool {
+	return s.Check(item) == nil
+}
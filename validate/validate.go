@@ -0,0 +1,126 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/* The registry maps names to validators so configuration files and
+ * the cli can refer to them without recompiling.
+ * Validators of different element types live in the same registry,
+ * so it has to store them as any. Lookup is generic and checks that
+ * the registered validator really works on the requested type,
+ * afterwards the caller has a fully typed Validator[T] again.
+ *
+ * A name may carry an argument after a colon ("contains:World"),
+ * in that case the part before the colon has to be registered
+ * as a factory building the validator from the argument.
+ */
+
+// error returned when no validator is registered under a name
+var ErrUnknown = errors.New("validate: unknown validator")
+
+// validation function for items of type T
+type Validator[T any] func(T) bool
+
+// validation function for items of type T that gives up once ctx is
+// done; the verdict of a cancelled validation does not matter
+type ContextValidator[T any] func(ctx context.Context, item T) bool
+
+// validator running v without a deadline
+func (v ContextValidator[T]) Validator() Validator[T] {
+	return func(item T) bool {
+		return v(context.Background(), item)
+	}
+}
+
+// context validator running v, which can not be cancelled
+func IgnoreContext[T any](v Validator[T]) ContextValidator[T] {
+	return func(_ context.Context, item T) bool {
+		return v(item)
+	}
+}
+
+// function building a validator from the argument of a name
+type Factory[T any] func(arg string) (Validator[T], error)
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]any)
+)
+
+// register v under name, v has to be a Validator[T], a func(T) bool or a Factory[T].
+// Registering the same name twice panics.
+func Register(name string, v any) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("validate: Register called twice for " + name)
+	}
+	registry[name] = v
+}
+
+// look up the validator registered under name for items of type T
+func Lookup[T any](name string) (Validator[T], error) {
+	mu.RLock()
+	v, ok := registry[name]
+	base, arg, with_arg := name, "", false
+	if !ok {
+		base, arg, with_arg = strings.Cut(name, ":")
+		if with_arg {
+			v, ok = registry[base]
+		}
+	}
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknown, name)
+	}
+
+	switch fn := v.(type) {
+	case Validator[T]:
+		if with_arg {
+			return nil, fmt.Errorf("validate: %q takes no argument", base)
+		}
+		return fn, nil
+	case func(T) bool:
+		if with_arg {
+			return nil, fmt.Errorf("validate: %q takes no argument", base)
+		}
+		return fn, nil
+	case Factory[T]:
+		return fn(arg)
+	case func(string) (Validator[T], error):
+		return fn(arg)
+	}
+	var zero T
+	return nil, fmt.Errorf("validate: %q does not validate %T", name, zero)
+}
+
+// sorted names of all registered validators
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// built-in validators
+func init() {
+	Register("even", func(n int) bool { return n%2 == 0 })
+	Register("odd", func(n int) bool { return n%2 != 0 })
+	Register("prime", IsPrime[int])
+	Register("nonempty", func(s string) bool { return s != "" })
+	Register("contains", Factory[string](func(arg string) (Validator[string], error) {
+		return func(s string) bool {
+			return strings.Contains(s, arg)
+		}, nil
+	}))
+}
@@ -0,0 +1,186 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+)
+
+/* The write-ahead log records every submitted item and every completed
+ * result as one JSON line. Items are serialized with a codec; items of the
+ * JSON codec are embedded as is to keep the log readable, anything else
+ * (e.g. gob) is stored base64 encoded. After a crash the log can be replayed:
+ * all submits without a matching result are still pending and have to be
+ * processed again, all others are already accounted for.
+ * Every append is synced to disk, so a record is either fully written
+ * or (if the process dies mid-write) ignored as a torn last line.
+ * An undecodable line anywhere else means the log is corrupt, Recover
+ * reports it instead of silently dropping the rest of the log.
+ */
+
+const (
+	opSubmit = "submit"
+	opResult = "result"
+)
+
+// single line in the log
+type record struct {
+	Op   string          `json:"op"`
+	ID   uint64          `json:"id"`
+	At   int64           `json:"at,omitempty"` // submission time in unix nanoseconds
+	Item json.RawMessage `json:"item,omitempty"`
+	Data []byte          `json:"data,omitempty"`
+}
+
+// pending item recovered from a log
+type Entry[T any] struct {
+	ID        uint64
+	Submitted time.Time // zero for logs written without it
+	Item      T
+}
+
+// append-only write-ahead log structure
+type Log[T any] struct {
+	mu    sync.Mutex
+	f     *os.File
+	codec codec.Codec[T]
+}
+
+// open the log at path for appending, creating it if necessary
+func Open[T any](path string, c codec.Codec[T]) (*Log[T], error) {
+	if err := trimTorn(path); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Log[T]{f: f, codec: c}, nil
+}
+
+// cut off a torn last line, appended records would be glued to it
+func trimTorn(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil || len(data) == 0 || data[len(data)-1] == '\n' {
+		return err
+	}
+	return os.Truncate(path, int64(bytes.LastIndexByte(data, '\n')+1))
+}
+
+// codec used for the items
+func (l *Log[T]) Codec() codec.Codec[T] {
+	return l.codec
+}
+
+// record that item was submitted with the given id at the given time
+func (l *Log[T]) Submit(id uint64, at time.Time, item T) error {
+	data, err := l.codec.Encode(item)
+	if err != nil {
+		return err
+	}
+	rec := record{Op: opSubmit, ID: id, At: at.UnixNano()}
+	if _, ok := l.codec.(codec.JSONCodec[T]); ok {
+		rec.Item = data
+	} else {
+		rec.Data = data
+	}
+	return l.append(rec)
+}
+
+// record that the item with the given id was processed
+func (l *Log[T]) Result(id uint64) error {
+	return l.append(record{Op: opResult, ID: id})
+}
+
+// write a record and sync it to disk
+func (l *Log[T]) append(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(line); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+// close the underlying file
+func (l *Log[T]) Close() error {
+	return l.f.Close()
+}
+
+// read the log at path and return all pending items ordered by id
+// together with the highest id found in the log.
+// A missing file is treated as an empty log.
+func Recover[T any](path string, c codec.Codec[T]) ([]Entry[T], uint64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	pending := make(map[uint64]Entry[T])
+	var last uint64
+	var torn error // undecodable line, only allowed as the last one
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if torn != nil {
+			return nil, 0, torn
+		}
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			torn = fmt.Errorf("wal %s: line %d: %w", path, n, err)
+			continue
+		}
+		last = max(last, rec.ID)
+		switch rec.Op {
+		case opSubmit:
+			data := rec.Data
+			if rec.Item != nil {
+				data = rec.Item
+			}
+			item, err := c.Decode(data)
+			if err != nil {
+				return nil, 0, err
+			}
+			e := Entry[T]{ID: rec.ID, Item: item}
+			if rec.At != 0 {
+				e.Submitted = time.Unix(0, rec.At)
+			}
+			pending[rec.ID] = e
+		case opResult:
+			delete(pending, rec.ID)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]Entry[T], 0, len(pending))
+	for _, e := range pending {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, last, nil
+}
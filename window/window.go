@@ -0,0 +1,126 @@
+package window
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juli-99/hka-modell_basierte_software/ring"
+)
+
+/* A sliding window aggregates the values recorded during the last span.
+ * The span is divided into buckets kept in a ring buffer, so memory is
+ * constant: a new bucket overwrites the oldest one, which by then lies
+ * outside the window anyway. The result is accurate up to one bucket width.
+ */
+
+// numeric types a window can aggregate
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// aggregate of the values recorded in one time slot
+type bucket[T Number] struct {
+	start time.Time
+	count int
+	sum   T
+}
+
+// generic sliding window structure, safe for concurrent use
+type Window[T Number] struct {
+	mu      sync.Mutex
+	span    time.Duration
+	width   time.Duration
+	buckets *ring.Ring[*bucket[T]]
+	now     func() time.Time
+}
+
+// create a new Window over span split into the given number of buckets
+func New[T Number](span time.Duration, buckets int) *Window[T] {
+	return &Window[T]{
+		span:    span,
+		width:   span / time.Duration(buckets),
+		buckets: ring.New[*bucket[T]](buckets),
+		now:     time.Now,
+	}
+}
+
+// record value at the current time
+func (w *Window[T]) Add(value T) {
+	w.AddAt(w.now(), value)
+}
+
+// record value at time t, t must not lie before earlier records
+func (w *Window[T]) AddAt(t time.Time, value T) {
+	start := t.Truncate(w.width)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b, ok := w.buckets.Newest()
+	if !ok || b.start.Before(start) {
+		b = &bucket[T]{start: start}
+		w.buckets.Push(b)
+	}
+	b.count++
+	b.sum += value
+}
+
+// number of values and their sum inside the window ending at now,
+// together with the start of the oldest bucket they were recorded in
+func (w *Window[T]) aggregate(now time.Time) (int, T, time.Time) {
+	from := now.Add(-w.span)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var count int
+	var sum T
+	var first time.Time
+	for _, b := range w.buckets.Snapshot() {
+		if b.start.Add(w.width).After(from) {
+			if count == 0 {
+				first = b.start
+			}
+			count += b.count
+			sum += b.sum
+		}
+	}
+	return count, sum, first
+}
+
+// number of values recorded inside the window
+func (w *Window[T]) Count() int {
+	count, _, _ := w.aggregate(w.now())
+	return count
+}
+
+// sum of the values recorded inside the window
+func (w *Window[T]) Sum() T {
+	_, sum, _ := w.aggregate(w.now())
+	return sum
+}
+
+// moving average of the values inside the window, zero if empty
+func (w *Window[T]) Mean() float64 {
+	count, sum, _ := w.aggregate(w.now())
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
+
+/* A window younger than its span has not seen a full span of records yet,
+ * so Rate only divides by the time since its oldest bucket started.
+ * That time is at least one bucket width, a single record right after
+ * a bucket started does not count as a huge rate.
+ */
+
+// values recorded per second, averaged over the span (or the time
+// since the first record inside it, if shorter)
+func (w *Window[T]) Rate() float64 {
+	now := w.now()
+	count, _, first := w.aggregate(now)
+	if count == 0 {
+		return 0
+	}
+	elapsed := min(w.span, max(now.Sub(first), w.width))
+	return float64(count) / elapsed.Seconds()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/juli-99/hka-modell_basierte_software/codec"
+	"github.com/juli-99/hka-modell_basierte_software/remote"
+	"github.com/juli-99/hka-modell_basierte_software/validate"
+)
+
+// serve an int validator to remote pools ("worker serve -addr :7070 -validator even")
+func workerCmd(args []string) error {
+	if len(args) == 0 || args[0] != "serve" {
+		return fmt.Errorf("usage: worker serve [-addr :7070] [-validator even]")
+	}
+	fs := flag.NewFlagSet("worker serve", flag.ExitOnError)
+	addr := fs.String("addr", ":7070", "address to listen on")
+	name := fs.String("validator", "even", "name of the int validator to serve")
+	fs.Parse(args[1:])
+
+	validator, err := validate.Lookup[int](*name)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("worker: serving on %s\n", l.Addr())
+	return remote.Serve(l, validator, codec.JSON[int](), codec.JSON[bool]())
+}